@@ -0,0 +1,407 @@
+package immut
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	MissingField = errors.New("missing required field")
+)
+
+// FromAny deep converts v into immut structures. Go maps and structs become HashMaps, slices
+// and arrays become Vectors and pointers and interfaces are followed. Struct fields are keyed by
+// name, or by the name in an `immut:"name"` tag, and fields tagged `immut:"-"` are skipped.
+// Fields tagged with the omitempty option, e.g. `immut:"name,omitempty"`, are left out when they
+// hold their zero value.
+// []byte values, HashMaps, Vectors, Lists and anything implementing encoding.TextMarshaler or
+// Byteser are kept as they are, as are all other values. v must not contain cycles.
+func FromAny(v interface{}) (interface{}, error) {
+	return fromValue(reflect.ValueOf(v))
+}
+
+func fromValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case *HashMap, *Vector, *List, encoding.TextMarshaler, Byteser:
+			return x, nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return fromValue(v.Elem())
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		h := NewHashMap()
+		iter := v.MapRange()
+		for iter.Next() {
+			x, err := fromValue(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			h = h.Put(iter.Key().Interface(), x)
+		}
+		return h, nil
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return append([]byte(nil), v.Bytes()...), nil
+		}
+		return fromSequence(v)
+
+	case reflect.Array:
+		return fromSequence(v)
+
+	case reflect.Struct:
+		return fromStruct(v)
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil, fmt.Errorf("immut: can't convert a %s", v.Type())
+	}
+
+	return v.Interface(), nil
+}
+
+// fromStruct converts the fields of a struct into a HashMap keyed by field name
+func fromStruct(v reflect.Value) (*HashMap, error) {
+	h := NewHashMap()
+	for _, f := range structFields(v.Type()) {
+		if f.omitEmpty && v.Field(f.index).IsZero() {
+			continue
+		}
+
+		x, err := fromValue(v.Field(f.index))
+		if err != nil {
+			return nil, fmt.Errorf("immut: field %s: %w", f.name, err)
+		}
+		h = h.Put(f.name, x)
+	}
+
+	return h, nil
+}
+
+// fromSequence converts every element of a slice or array into a Vector
+func fromSequence(v reflect.Value) (interface{}, error) {
+	vec := NewVector()
+	for i := 0; i < v.Len(); i++ {
+		x, err := fromValue(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		vec = vec.Put(i, x)
+	}
+
+	return vec, nil
+}
+
+// structField is an exported struct field, the key it is stored under and its tag options
+type structField struct {
+	index     int
+	name      string
+	omitEmpty bool
+	required  bool
+}
+
+// structFields returns the exported fields of t that aren't tagged `immut:"-"`. The tag holds
+// the key followed by comma separated options, omitempty and required.
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		sf := structField{
+			index: i,
+			name:  f.Name,
+		}
+
+		if tag, ok := f.Tag.Lookup("immut"); ok {
+			opts := strings.Split(tag, ",")
+			if opts[0] == "-" {
+				continue
+			}
+
+			if opts[0] != "" {
+				sf.name = opts[0]
+			}
+
+			for _, o := range opts[1:] {
+				switch o {
+				case "omitempty":
+					sf.omitEmpty = true
+				case "required":
+					sf.required = true
+				}
+			}
+		}
+
+		fields = append(fields, sf)
+	}
+
+	return fields
+}
+
+// ToAny deep converts immut structures back into plain Go values: HashMaps become
+// map[interface{}]interface{}, Vectors and Lists become []interface{}. Anything else is
+// returned as is. HashMap keys Go maps can't hold are converted with mapKey.
+func ToAny(v interface{}) interface{} {
+	switch x := v.(type) {
+	case *HashMap:
+		m := make(map[interface{}]interface{}, x.Len())
+		x.Each(func(k, v interface{}) {
+			m[mapKey(k)] = ToAny(v)
+		})
+		return m
+
+	case *Vector:
+		vals := x.values()
+		for i := range vals {
+			vals[i] = ToAny(vals[i])
+		}
+		return vals
+
+	case *List:
+		vals := x.ToSlice()
+		for i := range vals {
+			vals[i] = ToAny(vals[i])
+		}
+		return vals
+	}
+
+	return v
+}
+
+// mapKey returns a HashMap key as something a Go map can hold. A []byte becomes the string
+// HashMap already treats it the same as, and any other type Go can't compare becomes its
+// fmt.Sprint form, which is what the HashMap keyed it by.
+func mapKey(k interface{}) interface{} {
+	switch x := k.(type) {
+	case []byte:
+		return string(x)
+	case nil:
+		return nil
+	}
+
+	if !reflect.TypeOf(k).Comparable() {
+		return fmt.Sprint(k)
+	}
+
+	return k
+}
+
+// DecodeInto stores v, usually the result of FromAny, in the value ptr points to. HashMaps
+// decode into structs, using the same field names as FromAny, and into Go maps. Vectors and
+// Lists decode into slices and arrays. Numbers are converted between numeric types as long as
+// the value fits, and interface{} targets get the result of ToAny. Struct fields missing from
+// a HashMap are left untouched, unless they are tagged required, which makes them an error
+// wrapping MissingField.
+func DecodeInto(v interface{}, ptr interface{}) error {
+	p := reflect.ValueOf(ptr)
+	if p.Kind() != reflect.Ptr || p.IsNil() {
+		return fmt.Errorf("immut: DecodeInto needs a non nil pointer, got %T", ptr)
+	}
+
+	return decodeValue(v, p.Elem())
+}
+
+func decodeValue(v interface{}, dst reflect.Value) error {
+	if v == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	if dst.Kind() == reflect.Interface {
+		x := reflect.ValueOf(ToAny(v))
+		if !x.Type().AssignableTo(dst.Type()) {
+			return fmt.Errorf("immut: can't decode %T into %s", v, dst.Type())
+		}
+		dst.Set(x)
+		return nil
+	}
+
+	src := reflect.ValueOf(v)
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		n := reflect.New(dst.Type().Elem())
+		if err := decodeValue(v, n.Elem()); err != nil {
+			return err
+		}
+		dst.Set(n)
+		return nil
+
+	case reflect.Struct:
+		h, ok := v.(*HashMap)
+		if !ok {
+			break
+		}
+
+		for _, f := range structFields(dst.Type()) {
+			x, found := h.Get(f.name)
+			if !found {
+				if f.required {
+					return fmt.Errorf("immut: field %s: %w", f.name, MissingField)
+				}
+				continue
+			}
+
+			if err := decodeValue(x, dst.Field(f.index)); err != nil {
+				return fmt.Errorf("immut: field %s: %w", f.name, err)
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		h, ok := v.(*HashMap)
+		if !ok {
+			break
+		}
+
+		m := reflect.MakeMapWithSize(dst.Type(), h.Len())
+		var err error
+		h.Each(func(k, x interface{}) {
+			if err != nil {
+				return
+			}
+
+			mk := reflect.New(dst.Type().Key()).Elem()
+			if err = decodeValue(mapKey(k), mk); err != nil {
+				return
+			}
+			if mk.Kind() == reflect.Interface && !mk.IsNil() && !mk.Elem().Type().Comparable() {
+				err = fmt.Errorf("immut: can't use %T as a map key", mk.Elem().Interface())
+				return
+			}
+
+			mv := reflect.New(dst.Type().Elem()).Elem()
+			if err = decodeValue(x, mv); err != nil {
+				return
+			}
+			m.SetMapIndex(mk, mv)
+		})
+		if err != nil {
+			return err
+		}
+		dst.Set(m)
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		var vals []interface{}
+		switch x := v.(type) {
+		case *Vector:
+			vals = x.values()
+		case *List:
+			vals = x.ToSlice()
+		default:
+			return fmt.Errorf("immut: can't decode %T into %s", v, dst.Type())
+		}
+
+		if dst.Kind() == reflect.Slice {
+			dst.Set(reflect.MakeSlice(dst.Type(), len(vals), len(vals)))
+		} else if len(vals) > dst.Len() {
+			return fmt.Errorf("immut: can't decode %d values into %s", len(vals), dst.Type())
+		}
+
+		for i, x := range vals {
+			if err := decodeValue(x, dst.Index(i)); err != nil {
+				return fmt.Errorf("immut: index %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+
+	if x, ok := convertNumber(src, dst.Type()); ok {
+		dst.Set(x)
+		return nil
+	}
+
+	return fmt.Errorf("immut: can't decode %T into %s", v, dst.Type())
+}
+
+// convertNumber converts src to the numeric type t if it can be converted back without losing
+// anything
+func convertNumber(src reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if !isNumber(src.Kind()) || !isNumber(t.Kind()) {
+		return reflect.Value{}, false
+	}
+
+	x := src.Convert(t)
+	if x.Convert(src.Type()).Interface() != src.Interface() {
+		return reflect.Value{}, false
+	}
+
+	// converting between signed and unsigned types can round trip through the wrap around
+	if (src.CanInt() && src.Int() < 0 || src.CanFloat() && src.Float() < 0) && x.CanUint() {
+		return reflect.Value{}, false
+	}
+
+	if src.CanUint() && x.CanInt() && x.Int() < 0 {
+		return reflect.Value{}, false
+	}
+
+	return x, true
+}
+
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// Marshal converts the struct in, or the struct it points to, into a HashMap keyed by field
+// name, converting field values with FromAny. Field names and options come from immut tags the
+// way FromAny reads them. Unlike FromAny, the top level struct is always walked field by field,
+// even if it implements encoding.TextMarshaler or Byteser.
+func Marshal(in interface{}) (*HashMap, error) {
+	v := reflect.ValueOf(in)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("immut: can only marshal structs, got %T", in)
+	}
+
+	return fromStruct(v)
+}
+
+// Unmarshal fills the struct out points to from a HashMap keyed by field name, the reverse of
+// Marshal. Fields tagged required must be in the map, otherwise the error wraps MissingField.
+func Unmarshal(h *HashMap, out interface{}) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("immut: can only unmarshal into a pointer to a struct, got %T", out)
+	}
+
+	return decodeValue(h, v.Elem())
+}
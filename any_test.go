@@ -0,0 +1,246 @@
+package immut
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type anyInner struct {
+	Tags []string
+}
+
+type anyConfig struct {
+	Name    string
+	Port    int    `immut:"port"`
+	Secret  string `immut:"-"`
+	Inner   *anyInner
+	Limits  map[string]float64
+	Started time.Time
+	hidden  int
+}
+
+func TestFromAny(t *testing.T) {
+	now := time.Unix(100, 0)
+	c := anyConfig{
+		Name:    "svc",
+		Port:    8080,
+		Secret:  "x",
+		Inner:   &anyInner{Tags: []string{"a", "b"}},
+		Limits:  map[string]float64{"cpu": 1.5},
+		Started: now,
+		hidden:  1,
+	}
+
+	v, err := FromAny(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, ok := v.(*HashMap)
+	if !ok {
+		t.Fatalf("Expected a *HashMap got %T", v)
+	}
+
+	if h.Len() != 5 {
+		t.Errorf("Expected 5 fields got %v", h.Keys())
+	}
+
+	if p, _ := h.Get("port"); p != 8080 {
+		t.Errorf("Expected the tagged name to be used, got %v", p)
+	}
+
+	if _, found := h.Get("Secret"); found {
+		t.Error("Expected skipped fields to be left out")
+	}
+
+	inner, _ := h.Get("Inner")
+	tags, _ := inner.(*HashMap).Get("Tags")
+	if x, _ := tags.(*Vector).Get(1); x != "b" {
+		t.Errorf("Expected b got %v", x)
+	}
+
+	if s, _ := h.Get("Started"); s != now {
+		t.Errorf("Expected text marshalers to be kept as is, got %v", s)
+	}
+
+	if _, err := FromAny(map[string]interface{}{"f": func() {}}); err == nil {
+		t.Error("Expected an error converting a func")
+	}
+}
+
+func TestToAny(t *testing.T) {
+	v, _ := FromAny(map[string]interface{}{
+		"a": []interface{}{1, "x"},
+		"b": nil,
+	})
+
+	want := map[interface{}]interface{}{
+		"a": []interface{}{1, "x"},
+		"b": nil,
+	}
+
+	if got := ToAny(v); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v got %v", want, got)
+	}
+
+	if ToAny(ListFromSlice([]interface{}{1})).([]interface{})[0] != 1 {
+		t.Error("Expected lists to become slices")
+	}
+}
+
+func TestUnhashableKeys(t *testing.T) {
+	h := NewHashMap().Put([]byte("a"), 1).Put([]int{1, 2}, 2)
+
+	want := map[interface{}]interface{}{
+		"a":     1,
+		"[1 2]": 2,
+	}
+	if got := ToAny(h); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v got %v", want, got)
+	}
+
+	var plain map[interface{}]int
+	if err := DecodeInto(h, &plain); err != nil || plain["a"] != 1 || plain["[1 2]"] != 2 {
+		t.Errorf("Expected %v got %v %v", want, plain, err)
+	}
+
+	var byString map[string]int
+	if err := DecodeInto(NewHashMap().Put([]byte("a"), 1), &byString); err != nil || byString["a"] != 1 {
+		t.Errorf("Expected map[a:1] got %v %v", byString, err)
+	}
+
+	var nested map[interface{}]int
+	if err := DecodeInto(NewHashMap().Put(NewHashMap(), 1), &nested); err == nil {
+		t.Error("Expected a HashMap key to fail to decode into an interface{} key")
+	}
+}
+
+func TestDecodeInto(t *testing.T) {
+	c := anyConfig{
+		Name:    "svc",
+		Port:    8080,
+		Inner:   &anyInner{Tags: []string{"a"}},
+		Limits:  map[string]float64{"cpu": 2},
+		Started: time.Unix(5, 0),
+	}
+
+	v, _ := FromAny(c)
+
+	var out anyConfig
+	if err := DecodeInto(v, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(out, c) {
+		t.Errorf("Expected %+v got %+v", c, out)
+	}
+
+	// numbers decoded from JSON are float64
+	var n struct {
+		Count uint8
+		Ratio float32
+	}
+	h := NewHashMap().Put("Count", 3.0).Put("Ratio", 0.5)
+	if err := DecodeInto(h, &n); err != nil || n.Count != 3 || n.Ratio != 0.5 {
+		t.Errorf("Expected 3 and 0.5 got %+v, %v", n, err)
+	}
+
+	for _, bad := range []interface{}{3.5, -1.0, 300.0, math.NaN()} {
+		if err := DecodeInto(NewHashMap().Put("Count", bad), &n); err == nil {
+			t.Errorf("Expected %v not to fit in a uint8", bad)
+		}
+	}
+
+	var i int64
+	if err := DecodeInto(uint64(math.MaxUint64), &i); err == nil {
+		t.Error("Expected MaxUint64 not to fit in an int64")
+	}
+
+	var plain interface{}
+	if err := DecodeInto(NewVector().Put(0, 1), &plain); err != nil || !reflect.DeepEqual(plain, []interface{}{1}) {
+		t.Errorf("Expected interface{} targets to get plain values, got %v", plain)
+	}
+
+	var arr [1]int
+	if err := DecodeInto(NewVector().Put(0, 1).Put(1, 2), &arr); err == nil {
+		t.Error("Expected an error for too many values")
+	}
+
+	if err := DecodeInto(1, out); err == nil {
+		t.Error("Expected an error for a non pointer")
+	}
+}
+
+type bindRequest struct {
+	User  string `immut:"user,required"`
+	Note  string `immut:"note,omitempty"`
+	Count int    `immut:"count"`
+}
+
+func TestMarshal(t *testing.T) {
+	h, err := Marshal(&bindRequest{User: "ann", Count: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h.Len() != 2 {
+		t.Errorf("Expected the empty note to be left out, got %v", h.Keys())
+	}
+
+	if u, _ := h.Get("user"); u != "ann" {
+		t.Errorf("Expected ann got %v", u)
+	}
+
+	if _, err := Marshal(3); err == nil {
+		t.Error("Expected an error marshaling a non struct")
+	}
+}
+
+// byteKey is a struct that is also a Byteser, which FromAny keeps as is
+type byteKey struct {
+	ID   string
+	Part int
+}
+
+func (b byteKey) Bytes() []byte {
+	return []byte(fmt.Sprintf("%s/%d", b.ID, b.Part))
+}
+
+func TestMarshalByteser(t *testing.T) {
+	h, err := Marshal(byteKey{ID: "a", Part: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id, _ := h.Get("ID"); id != "a" || h.Len() != 2 {
+		t.Errorf("Expected the fields of the struct got %v", h.Keys())
+	}
+
+	if _, err := Marshal(time.Unix(0, 0)); err != nil {
+		t.Errorf("Expected a TextMarshaler struct to marshal, got %v", err)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var r bindRequest
+	if err := Unmarshal(NewHashMap().Put("user", "bo").Put("count", 5.0), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.User != "bo" || r.Count != 5 {
+		t.Errorf("Unexpected struct %+v", r)
+	}
+
+	err := Unmarshal(NewHashMap().Put("count", 1), &r)
+	if !errors.Is(err, MissingField) {
+		t.Errorf("Expected MissingField got %v", err)
+	}
+
+	if err := Unmarshal(NewHashMap(), r); err == nil {
+		t.Error("Expected an error unmarshaling into a non pointer")
+	}
+}
@@ -0,0 +1,134 @@
+package immut
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+)
+
+var (
+	InvalidFalsePositiveRate = errors.New("false positive rate must be between 0 and 1")
+	InvalidFilter            = errors.New("invalid bloom filter encoding")
+)
+
+// bloomVersion is the first byte of an encoded BloomFilter
+const bloomVersion = 1
+
+// A BloomFilter answers whether a key may be in the map it was built from. A false answer is
+// certain, a true one is wrong at about the rate the filter was built for. Filters never change
+// once built, so one can be kept next to the snapshot it describes and checked before Get to
+// skip definite misses cheaply.
+type BloomFilter struct {
+	words []uint64
+	m     uint64
+	k     uint32
+}
+
+// BuildFilter builds a BloomFilter holding every key in the map, sized so keys that aren't in
+// it are reported as present at about fpRate, which must be between 0 and 1.
+func (h *HashMap) BuildFilter(fpRate float64) (*BloomFilter, error) {
+	if !(fpRate > 0 && fpRate < 1) {
+		return nil, InvalidFalsePositiveRate
+	}
+
+	n := float64(max(h.Len(), 1))
+	m := uint64(math.Ceil(-n * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	f := &BloomFilter{
+		words: make([]uint64, (m+63)/64),
+		m:     m,
+		k:     uint32(max(1, math.Round(float64(m)/n*math.Ln2))),
+	}
+
+	h.Each(func(k, v interface{}) {
+		f.add(iToBytes(k))
+	})
+
+	return f, nil
+}
+
+// MayContain returns false if k is certainly not in the map the filter was built from. The
+// zero BloomFilter knows nothing, so it may contain every key.
+func (f *BloomFilter) MayContain(k interface{}) bool {
+	if f.m == 0 {
+		return true
+	}
+
+	h1, h2 := bloomHash(iToBytes(k))
+	for i := uint64(0); i < uint64(f.k); i++ {
+		b := (h1 + i*h2) % f.m
+		if f.words[b/64]&(1<<(b%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// add sets the bits of an encoded key
+func (f *BloomFilter) add(key []byte) {
+	h1, h2 := bloomHash(key)
+	for i := uint64(0); i < uint64(f.k); i++ {
+		b := (h1 + i*h2) % f.m
+		f.words[b/64] |= 1 << (b % 64)
+	}
+}
+
+// bloomHash splits the 64 bit FNV-1a hash of a key into the two hashes every bit index is
+// derived from. It doesn't use the map's Hasher, so a filter means the same thing whichever
+// Hasher its map had.
+func bloomHash(key []byte) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write(key)
+	s := h.Sum64()
+
+	// an odd step never lands on the same bit twice in a row
+	return s & 0xffffffff, s>>32 | 1
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, which gob also uses. The encoding is
+//
+//	byte 0:     format version, currently bloomVersion
+//	bytes 1-4:  number of hashes per key, little endian
+//	bytes 5-12: number of bits, little endian
+//	rest:       the bits, 8 bytes per word, little endian
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 13, 13+8*len(f.words))
+	b[0] = bloomVersion
+	binary.LittleEndian.PutUint32(b[1:], f.k)
+	binary.LittleEndian.PutUint64(b[5:], f.m)
+	for _, w := range f.words {
+		b = binary.LittleEndian.AppendUint64(b, w)
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 13 {
+		return InvalidFilter
+	}
+	if data[0] != bloomVersion {
+		return UnsupportedVersion
+	}
+
+	k := binary.LittleEndian.Uint32(data[1:])
+	m := binary.LittleEndian.Uint64(data[5:])
+	rest := data[13:]
+	if k == 0 || m == 0 || uint64(len(rest)) != (m+63)/64*8 {
+		return InvalidFilter
+	}
+
+	words := make([]uint64, len(rest)/8)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(rest[8*i:])
+	}
+
+	*f = BloomFilter{
+		words: words,
+		m:     m,
+		k:     k,
+	}
+	return nil
+}
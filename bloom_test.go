@@ -0,0 +1,85 @@
+package immut
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func TestBloomFilter(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 10000; i++ {
+		h = h.Put(i, i)
+	}
+
+	f, err := h.BuildFilter(0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10000; i++ {
+		if !f.MayContain(i) {
+			t.Fatalf("Expected %d to be in the filter", i)
+		}
+	}
+
+	hits := 0
+	for i := 10000; i < 20000; i++ {
+		if f.MayContain(i) {
+			hits++
+		}
+	}
+
+	if hits > 300 {
+		t.Errorf("Expected about 1%% false positives, got %d in 10000", hits)
+	}
+}
+
+func TestBloomFilterRate(t *testing.T) {
+	for _, rate := range []float64{0, 1, -1, 2} {
+		if _, err := NewHashMap().BuildFilter(rate); err != InvalidFalsePositiveRate {
+			t.Errorf("Expected InvalidFalsePositiveRate for %v got %v", rate, err)
+		}
+	}
+
+	f, err := NewHashMap().BuildFilter(0.5)
+	if err != nil || f.MayContain("a") && f.MayContain("b") && f.MayContain("c") {
+		t.Errorf("Expected an empty map's filter to reject keys, got %v", err)
+	}
+}
+
+func TestBloomFilterEncoding(t *testing.T) {
+	h := NewHashMap().Put("a", 1).Put("b", 2)
+	f, _ := h.BuildFilter(0.001)
+
+	b := bytes.NewBuffer(nil)
+	if err := gob.NewEncoder(b).Encode(f); err != nil {
+		t.Fatal(err)
+	}
+
+	var n BloomFilter
+	if err := gob.NewDecoder(b).Decode(&n); err != nil {
+		t.Fatal(err)
+	}
+
+	if !n.MayContain("a") || !n.MayContain("b") || n.k != f.k || n.m != f.m {
+		t.Error("Expected the decoded filter to match")
+	}
+
+	data, _ := f.MarshalBinary()
+	if err := n.UnmarshalBinary(data[:len(data)-1]); err != InvalidFilter {
+		t.Errorf("Expected InvalidFilter got %v", err)
+	}
+
+	data[0] = 9
+	if err := n.UnmarshalBinary(data); err != UnsupportedVersion {
+		t.Errorf("Expected UnsupportedVersion got %v", err)
+	}
+}
+
+func TestBloomFilterZero(t *testing.T) {
+	var f BloomFilter
+	if !f.MayContain("a") {
+		t.Error("Expected the zero filter to rule nothing out")
+	}
+}
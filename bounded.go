@@ -0,0 +1,206 @@
+package immut
+
+// EvictionPolicy decides which entry a BoundedMap drops when it grows past its max size
+type EvictionPolicy int
+
+const (
+	// FIFO evicts the entry that was inserted first
+	FIFO EvictionPolicy = iota
+
+	// Clock evicts the oldest entry that has not been touched since it was last passed over,
+	// giving touched entries a second chance at the back of the queue
+	Clock
+)
+
+// A BoundedMap is a HashMap that holds at most max entries. Put returns a new version that may
+// have evicted an old entry according to the map's EvictionPolicy.
+type BoundedMap struct {
+	entries *HashMap
+	order   queue // of boundedKey, oldest first
+	max     int
+	policy  EvictionPolicy
+	seq     uint64
+}
+
+// boundedEntry is the value stored in the underlying HashMap for every key
+type boundedEntry struct {
+	val     interface{}
+	seq     uint64
+	touched bool
+}
+
+// boundedKey is a node in the eviction queue. Deletes don't rewrite the queue, so the seq is
+// used to skip nodes whose key has since been removed or reinserted.
+type boundedKey struct {
+	key interface{}
+	seq uint64
+}
+
+// NewBoundedMap returns an empty BoundedMap that holds at most max entries. A max below 1 is
+// treated as 1.
+func NewBoundedMap(max int, policy EvictionPolicy) *BoundedMap {
+	if max < 1 {
+		max = 1
+	}
+
+	return &BoundedMap{
+		entries: NewHashMap(),
+		max:     max,
+		policy:  policy,
+	}
+}
+
+// Len returns the number of entries in the map
+func (b *BoundedMap) Len() int {
+	return b.entries.Len()
+}
+
+// Get returns the value stored at the given key
+func (b *BoundedMap) Get(k interface{}) (interface{}, bool) {
+	v, found := b.entries.Get(k)
+	if !found {
+		return nil, false
+	}
+
+	return v.(boundedEntry).val, true
+}
+
+// Each runs f on every k,v pair
+func (b *BoundedMap) Each(f func(k, v interface{})) {
+	b.entries.Each(func(k, v interface{}) {
+		f(k, v.(boundedEntry).val)
+	})
+}
+
+// Put stores v at k, evicting entries if the map grows past its max size. Overwriting a key
+// keeps its place in the eviction order.
+func (b *BoundedMap) Put(k, v interface{}) *BoundedMap {
+	n := *b
+	if old, found := b.entries.Get(k); found {
+		e := old.(boundedEntry)
+		n.entries = b.entries.Put(k, boundedEntry{val: v, seq: e.seq, touched: true})
+		return &n
+	}
+
+	n.seq++
+	n.entries = b.entries.Put(k, boundedEntry{val: v, seq: n.seq})
+	n.order = b.order.push(boundedKey{key: k, seq: n.seq})
+	n.evict()
+	return &n
+}
+
+// Touch marks the entry at k as recently used. It only has an effect under the Clock policy.
+func (b *BoundedMap) Touch(k interface{}) *BoundedMap {
+	if b.policy != Clock {
+		return b
+	}
+
+	v, found := b.entries.Get(k)
+	if !found || v.(boundedEntry).touched {
+		return b
+	}
+
+	e := v.(boundedEntry)
+	e.touched = true
+	n := *b
+	n.entries = b.entries.Put(k, e)
+	return &n
+}
+
+// Del removes the entry stored at k
+func (b *BoundedMap) Del(k interface{}) *BoundedMap {
+	n := *b
+	n.entries, _ = b.entries.Del(k)
+	n.compact()
+	return &n
+}
+
+// evict pops entries off the front of the queue until the map fits in max
+func (b *BoundedMap) evict() {
+	for b.entries.Len() > b.max {
+		x, order, ok := b.order.pop()
+		if !ok {
+			break
+		}
+		b.order = order
+
+		k := x.(boundedKey)
+		if !b.live(k) {
+			continue
+		}
+
+		v, _ := b.entries.Get(k.key)
+		e := v.(boundedEntry)
+		if b.policy == Clock && e.touched {
+			e.touched = false
+			b.entries = b.entries.Put(k.key, e)
+			b.order = b.order.push(k)
+			continue
+		}
+
+		b.entries, _ = b.entries.Del(k.key)
+	}
+
+	b.compact()
+}
+
+// live returns true if k is the current queue node for its key
+func (b *BoundedMap) live(k boundedKey) bool {
+	v, found := b.entries.Get(k.key)
+	return found && v.(boundedEntry).seq == k.seq
+}
+
+// compact rebuilds the queue without its stale nodes once they outnumber the live ones, so
+// deleting keys can't grow the queue without bound
+func (b *BoundedMap) compact() {
+	if b.order.size <= 2*b.entries.Len()+8 {
+		return
+	}
+
+	var q queue
+	for {
+		x, rest, ok := b.order.pop()
+		if !ok {
+			break
+		}
+		b.order = rest
+
+		if b.live(x.(boundedKey)) {
+			q = q.push(x)
+		}
+	}
+	b.order = q
+}
+
+// queue is a persistent FIFO queue made of two lists. Values are pushed onto the back list and
+// popped off the front list, which is refilled by reversing the back list when it runs dry.
+type queue struct {
+	front *List
+	back  *List
+	size  int
+}
+
+// push adds v to the back of the queue
+func (q queue) push(v interface{}) queue {
+	return queue{
+		front: q.front,
+		back:  q.back.Prepend(v),
+		size:  q.size + 1,
+	}
+}
+
+// pop removes the value at the front of the queue
+func (q queue) pop() (interface{}, queue, bool) {
+	if q.front == nil {
+		for y := q.back; y != nil; y = y.next {
+			q.front = q.front.Prepend(y.val)
+		}
+		q.back = nil
+	}
+
+	if q.front == nil {
+		return nil, q, false
+	}
+
+	return q.front.val, queue{front: q.front.next, back: q.back, size: q.size - 1}, true
+}
@@ -0,0 +1,113 @@
+package immut
+
+import "testing"
+
+func TestBoundedMapFIFO(t *testing.T) {
+	b := NewBoundedMap(3, FIFO)
+	for i := 0; i < 5; i++ {
+		b = b.Put(i, i)
+	}
+
+	if b.Len() != 3 {
+		t.Errorf("Expected 3 got %d", b.Len())
+	}
+
+	for i := 0; i < 5; i++ {
+		_, found := b.Get(i)
+		if want := i >= 2; found != want {
+			t.Errorf("Expected %d found to be %t", i, want)
+		}
+	}
+
+	// overwriting keeps the original position
+	x := b.Put(2, 20).Put(5, 5)
+	if _, found := x.Get(2); found {
+		t.Error("2 should have been evicted")
+	}
+
+	if v, _ := b.Get(2); v != 2 {
+		t.Errorf("Persistance broken. Expected 2 got %v", v)
+	}
+}
+
+func TestBoundedMapDelReinsert(t *testing.T) {
+	b := NewBoundedMap(2, FIFO).Put("a", 1).Put("b", 2).Del("a").Put("a", 3).Put("c", 4)
+
+	if _, found := b.Get("b"); found {
+		t.Error("b should have been evicted")
+	}
+
+	if v, _ := b.Get("a"); v != 3 {
+		t.Errorf("Expected 3 got %v", v)
+	}
+}
+
+func TestBoundedMapClock(t *testing.T) {
+	b := NewBoundedMap(3, Clock).Put(0, 0).Put(1, 1).Put(2, 2).Touch(0).Put(3, 3)
+
+	if _, found := b.Get(0); !found {
+		t.Error("0 was touched and should have had a second chance")
+	}
+
+	if _, found := b.Get(1); found {
+		t.Error("1 should have been evicted")
+	}
+
+	if b.Len() != 3 {
+		t.Errorf("Expected 3 got %d", b.Len())
+	}
+}
+
+func TestQueue(t *testing.T) {
+	var q queue
+	for i := 0; i < 3; i++ {
+		q = q.push(i)
+	}
+
+	v, q, _ := q.pop()
+	q = q.push(3)
+	for want := 0; want < 4; want++ {
+		if v != want {
+			t.Fatalf("Expected %d got %v", want, v)
+		}
+		v, q, _ = q.pop()
+	}
+
+	if _, _, ok := q.pop(); ok {
+		t.Error("Queue should be empty")
+	}
+}
+
+func TestBoundedMapSmallMax(t *testing.T) {
+	for _, max := range []int{-1, 0} {
+		b := NewBoundedMap(max, FIFO).Put("a", 1).Put("b", 2)
+		if b.Len() != 1 {
+			t.Errorf("Expected a max of %d to hold 1 entry, got %d", max, b.Len())
+		}
+
+		if v, _ := b.Get("b"); v != 2 {
+			t.Errorf("Expected the newest entry to be kept, got %v", v)
+		}
+	}
+}
+
+func TestBoundedMapDelCompacts(t *testing.T) {
+	for _, policy := range []EvictionPolicy{FIFO, Clock} {
+		b := NewBoundedMap(10, policy)
+		for i := 0; i < 1000; i++ {
+			b = b.Put(i, i).Del(i)
+		}
+		b = b.Put("a", 1).Put("b", 2)
+
+		if b.Len() != 2 || b.order.size > 2*b.Len()+8 {
+			t.Errorf("Expected stale queue nodes to be dropped, have %d for %d entries", b.order.size, b.Len())
+		}
+
+		for i := 0; i < 20; i++ {
+			b = b.Put(i, i)
+		}
+		if b.Len() != 10 {
+			t.Errorf("Expected 10 entries got %d", b.Len())
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package immut
+
+import (
+	"bytes"
+	"hash/fnv"
+	"io"
+)
+
+const (
+	// smallChunk is the longest pair of chunks Concat copies into one instead of linking, so
+	// building a string a few bytes at a time doesn't leave a node per write
+	smallChunk = 64
+
+	// maxBytesDepth is how deep a Bytes may get before Concat rebalances it
+	maxBytesDepth = 48
+)
+
+// Bytes is an immutable byte string made of shared chunks. Concat links two strings without
+// copying either and Slice reuses the chunks it covers, so messages built from common fragments
+// share their memory with those fragments. A Bytes implements Byteser, so it can be used as a
+// HashMap key.
+type Bytes struct {
+	left, right *Bytes
+
+	// chunk holds the bytes of a leaf, it's never written to after the leaf is made
+	chunk []byte
+
+	n     int
+	depth int
+}
+
+// NewBytes returns a byte string holding a copy of b
+func NewBytes(b []byte) *Bytes {
+	c := make([]byte, len(b))
+	copy(c, b)
+	return NewBytesOwned(c)
+}
+
+// NewBytesOwned returns a byte string holding b without copying it. The caller must not
+// modify b afterwards.
+func NewBytesOwned(b []byte) *Bytes {
+	return &Bytes{
+		chunk: b[:len(b):len(b)],
+		n:     len(b),
+	}
+}
+
+// Len returns the number of bytes in the string
+func (b *Bytes) Len() int {
+	return b.n
+}
+
+// leaf returns true if the string is a single chunk
+func (b *Bytes) leaf() bool {
+	return b.left == nil
+}
+
+// Concat returns b followed by o. Neither string is copied unless both are small chunks.
+func (b *Bytes) Concat(o *Bytes) *Bytes {
+	if b.n == 0 {
+		return o
+	}
+	if o.n == 0 {
+		return b
+	}
+
+	if b.leaf() && o.leaf() && b.n+o.n <= smallChunk {
+		c := make([]byte, 0, b.n+o.n)
+		c = append(c, b.chunk...)
+		return NewBytesOwned(append(c, o.chunk...))
+	}
+
+	n := &Bytes{
+		left:  b,
+		right: o,
+		n:     b.n + o.n,
+		depth: max(b.depth, o.depth) + 1,
+	}
+
+	if n.depth > maxBytesDepth {
+		var chunks [][]byte
+		n.eachChunk(func(c []byte) bool {
+			chunks = append(chunks, c)
+			return true
+		})
+		return balancedBytes(chunks)
+	}
+
+	return n
+}
+
+// balancedBytes builds a string of the smallest depth out of the given chunks
+func balancedBytes(chunks [][]byte) *Bytes {
+	if len(chunks) == 1 {
+		return NewBytesOwned(chunks[0])
+	}
+
+	l := balancedBytes(chunks[:len(chunks)/2])
+	r := balancedBytes(chunks[len(chunks)/2:])
+	return &Bytes{
+		left:  l,
+		right: r,
+		n:     l.n + r.n,
+		depth: max(l.depth, r.depth) + 1,
+	}
+}
+
+// Slice returns the bytes in [start, end) as a new string, the same as the built in
+// myBytes[start:end]. It returns IndexOutOfRange unless 0 <= start <= end <= Len. No bytes are
+// copied, the new string points into b's chunks.
+func (b *Bytes) Slice(start, end int) (*Bytes, error) {
+	if start < 0 || end > b.n || start > end {
+		return nil, IndexOutOfRange
+	}
+
+	return b.slice(start, end), nil
+}
+
+func (b *Bytes) slice(start, end int) *Bytes {
+	if start == 0 && end == b.n {
+		return b
+	}
+
+	if b.leaf() {
+		return NewBytesOwned(b.chunk[start:end])
+	}
+
+	ln := b.left.n
+	if end <= ln {
+		return b.left.slice(start, end)
+	}
+	if start >= ln {
+		return b.right.slice(start-ln, end-ln)
+	}
+
+	return b.left.slice(start, ln).Concat(b.right.slice(0, end-ln))
+}
+
+// At returns the byte at index i
+func (b *Bytes) At(i int) (byte, bool) {
+	if i < 0 || i >= b.n {
+		return 0, false
+	}
+
+	for !b.leaf() {
+		if i < b.left.n {
+			b = b.left
+		} else {
+			i -= b.left.n
+			b = b.right
+		}
+	}
+
+	return b.chunk[i], true
+}
+
+// Index returns the index of the first instance of sep in b, or -1 if it isn't there, like
+// bytes.Index. Matches that span chunks are found without joining the chunks.
+func (b *Bytes) Index(sep []byte) int {
+	if len(sep) == 0 {
+		return 0
+	}
+
+	// tail is the end of what has been searched, too short to hold sep but long enough to start
+	// a match that runs into the next chunk
+	var tail []byte
+	found := -1
+	off := 0
+	b.eachChunk(func(c []byte) bool {
+		if len(tail) > 0 {
+			w := append(tail[:len(tail):len(tail)], c[:min(len(c), len(sep)-1)]...)
+			if i := bytes.Index(w, sep); i >= 0 {
+				found = off - len(tail) + i
+				return false
+			}
+		}
+
+		if i := bytes.Index(c, sep); i >= 0 {
+			found = off + i
+			return false
+		}
+
+		tail = append(tail, c[max(0, len(c)-len(sep)+1):]...)
+		if len(tail) > len(sep)-1 {
+			tail = append([]byte(nil), tail[len(tail)-len(sep)+1:]...)
+		}
+		off += len(c)
+		return true
+	})
+
+	return found
+}
+
+// Equal returns true if both strings hold the same bytes, however they are chunked
+func (b *Bytes) Equal(o *Bytes) bool {
+	if b == o {
+		return true
+	}
+	if b.n != o.n {
+		return false
+	}
+
+	r := o.Reader()
+	var buf []byte
+	return b.eachChunk(func(c []byte) bool {
+		if cap(buf) < len(c) {
+			buf = make([]byte, len(c))
+		}
+		buf = buf[:len(c)]
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return false
+		}
+
+		return bytes.Equal(c, buf)
+	})
+}
+
+// Hash returns the FNV-32 hash of the string, the same as hashing Bytes() with the default
+// Hasher but without joining the chunks
+func (b *Bytes) Hash() uint32 {
+	h := fnv.New32()
+	b.eachChunk(func(c []byte) bool {
+		h.Write(c)
+		return true
+	})
+
+	return h.Sum32()
+}
+
+// Bytes returns the string as a newly allocated []byte
+func (b *Bytes) Bytes() []byte {
+	out := make([]byte, 0, b.n)
+	b.eachChunk(func(c []byte) bool {
+		out = append(out, c...)
+		return true
+	})
+
+	return out
+}
+
+// String returns the string as a Go string
+func (b *Bytes) String() string {
+	return string(b.Bytes())
+}
+
+// Reader returns an io.Reader over the string. Reading never copies chunks other than into the
+// caller's buffer.
+func (b *Bytes) Reader() io.Reader {
+	return &bytesReader{
+		stack: []*Bytes{b},
+	}
+}
+
+// eachChunk runs f on every non empty chunk in order until f returns false. It returns false
+// if it was stopped early.
+func (b *Bytes) eachChunk(f func([]byte) bool) bool {
+	if b.leaf() {
+		return len(b.chunk) == 0 || f(b.chunk)
+	}
+
+	return b.left.eachChunk(f) && b.right.eachChunk(f)
+}
+
+// bytesReader reads a Bytes one chunk at a time, keeping the subtrees left to read on a stack
+type bytesReader struct {
+	stack []*Bytes
+	cur   []byte
+}
+
+// next moves to the next non empty chunk, it returns false at the end of the string
+func (r *bytesReader) next() bool {
+	for len(r.cur) == 0 {
+		if len(r.stack) == 0 {
+			return false
+		}
+
+		b := r.stack[len(r.stack)-1]
+		r.stack = r.stack[:len(r.stack)-1]
+		if b.leaf() {
+			r.cur = b.chunk
+			continue
+		}
+		r.stack = append(r.stack, b.right, b.left)
+	}
+
+	return true
+}
+
+// Read implements io.Reader
+func (r *bytesReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	n := 0
+	for n < len(p) && r.next() {
+		c := copy(p[n:], r.cur)
+		r.cur = r.cur[c:]
+		n += c
+	}
+
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// WriteTo implements io.WriterTo, writing each chunk straight to w
+func (r *bytesReader) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for r.next() {
+		n, err := w.Write(r.cur)
+		total += int64(n)
+		r.cur = r.cur[n:]
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
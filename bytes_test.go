@@ -0,0 +1,133 @@
+package immut
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+// chunkedBytes builds a Bytes holding b out of chunks of random lengths
+func chunkedBytes(r *rand.Rand, b []byte) *Bytes {
+	x := NewBytes(nil)
+	for len(b) > 0 {
+		n := 1 + r.Intn(min(len(b), 100))
+		x = x.Concat(NewBytes(b[:n]))
+		b = b[n:]
+	}
+
+	return x
+}
+
+func TestBytesConcatSlice(t *testing.T) {
+	hello := NewBytes([]byte("hello "))
+	world := NewBytes([]byte("world"))
+	x := hello.Concat(world)
+	if x.String() != "hello world" || x.Len() != 11 {
+		t.Fatalf("Expected hello world got %q", x.String())
+	}
+
+	if hello.String() != "hello " {
+		t.Errorf("Expected Concat to leave its operands alone, got %q", hello.String())
+	}
+
+	s, err := x.Slice(3, 8)
+	if err != nil || s.String() != "lo wo" {
+		t.Errorf("Expected lo wo got %q %v", s, err)
+	}
+
+	if _, err := x.Slice(3, 12); err != IndexOutOfRange {
+		t.Errorf("Expected IndexOutOfRange got %v", err)
+	}
+
+	if _, err := x.Slice(4, 3); err != IndexOutOfRange {
+		t.Errorf("Expected IndexOutOfRange got %v", err)
+	}
+
+	if c, found := x.At(6); !found || c != 'w' {
+		t.Errorf("Expected w got %q", c)
+	}
+
+	if _, found := x.At(11); found {
+		t.Error("Expected At past the end to fail")
+	}
+}
+
+func TestBytesSliceShares(t *testing.T) {
+	b := []byte("0123456789")
+	x := NewBytesOwned(b)
+	s, _ := x.Slice(2, 5)
+
+	// the slice points into the chunk it came from
+	if &s.chunk[0] != &b[2] {
+		t.Error("Expected Slice not to copy")
+	}
+
+	if s.Concat(NewBytes([]byte("x"))).String() != "234x" || x.String() != "0123456789" {
+		t.Error("Expected Concat on a slice not to write into the original chunk")
+	}
+}
+
+func TestBytesRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		want := make([]byte, r.Intn(2000))
+		for j := range want {
+			want[j] = byte('a' + r.Intn(3))
+		}
+		x := chunkedBytes(r, want)
+
+		if !bytes.Equal(x.Bytes(), want) || x.Len() != len(want) {
+			t.Fatalf("Expected %q got %q", want, x.Bytes())
+		}
+
+		if x.depth > maxBytesDepth {
+			t.Fatalf("Expected depth at most %d got %d", maxBytesDepth, x.depth)
+		}
+
+		if y := chunkedBytes(r, want); !x.Equal(y) || x.Hash() != y.Hash() || x.Hash() != hashKey(want) {
+			t.Fatal("Expected differently chunked copies to be equal and hash the same")
+		}
+
+		got, err := io.ReadAll(x.Reader())
+		if err != nil || !bytes.Equal(got, want) {
+			t.Fatalf("Expected the reader to return %q got %q %v", want, got, err)
+		}
+
+		start := r.Intn(len(want) + 1)
+		end := start + r.Intn(len(want)-start+1)
+		s, err := x.Slice(start, end)
+		if err != nil || !bytes.Equal(s.Bytes(), want[start:end]) {
+			t.Fatalf("Expected %q got %q", want[start:end], s.Bytes())
+		}
+
+		for k := 0; k < 5; k++ {
+			sep := []byte("abcabc")[:1+r.Intn(6)]
+			if got, want := x.Index(sep), bytes.Index(want, sep); got != want {
+				t.Fatalf("Expected Index(%q) to be %d got %d", sep, want, got)
+			}
+		}
+
+		if x.Index([]byte("d")) != -1 || x.Index(nil) != 0 {
+			t.Fatal("Expected missing and empty separators to match bytes.Index")
+		}
+	}
+}
+
+func TestBytesEqual(t *testing.T) {
+	a := NewBytes([]byte("abc"))
+	if a.Equal(NewBytes([]byte("abd"))) || a.Equal(NewBytes([]byte("ab"))) {
+		t.Error("Expected different strings not to be equal")
+	}
+
+	if !NewBytes(nil).Equal(NewBytes([]byte{})) {
+		t.Error("Expected empty strings to be equal")
+	}
+}
+
+func TestBytesKey(t *testing.T) {
+	h := NewHashMap().Put(NewBytes([]byte("a")).Concat(NewBytes([]byte("b"))), 1)
+	if v, found := h.Get(NewBytes([]byte("ab"))); !found || v != 1 {
+		t.Errorf("Expected equal strings to be the same key, got %v", v)
+	}
+}
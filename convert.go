@@ -0,0 +1,96 @@
+package immut
+
+import "fmt"
+
+// ToList returns the vector's values as a list in index order
+func (v *Vector) ToList() *List {
+	return ListFromSlice(v.values())
+}
+
+// ToVector returns the list's values as a vector indexed from 0
+func (l *List) ToVector() *Vector {
+	v := NewVector()
+	i := 0
+	l.Each(func(x interface{}) {
+		v = v.Put(i, x)
+		i++
+	})
+
+	return v
+}
+
+// ToVector returns the map's k,v pairs as a vector of Pair values in traversal order
+func (h *HashMap) ToVector() *Vector {
+	v := NewVector()
+	i := 0
+	h.Each(func(k, val interface{}) {
+		v = v.Put(i, Pair{Key: k, Val: val})
+		i++
+	})
+
+	return v
+}
+
+// KeysVector returns the map's keys as a vector in traversal order
+func (h *HashMap) KeysVector() *Vector {
+	v := NewVector()
+	i := 0
+	h.Each(func(k, _ interface{}) {
+		v = v.Put(i, k)
+		i++
+	})
+
+	return v
+}
+
+// KeysList returns the map's keys as a list in traversal order
+func (h *HashMap) KeysList() *List {
+	var l *List
+	h.Each(func(k, _ interface{}) {
+		l = l.Prepend(k)
+	})
+
+	return l.Reverse()
+}
+
+// HashMapFromVector builds a map from a vector of Pair values, like the one returned by
+// HashMap.ToVector. Later pairs win over earlier ones with the same key.
+func HashMapFromVector(v *Vector) (*HashMap, error) {
+	h := NewHashMap()
+	for i, x := range v.values() {
+		p, ok := x.(Pair)
+		if !ok {
+			return nil, fmt.Errorf("immut: vector element %d is a %T, not a Pair", i, x)
+		}
+		h = h.Put(p.Key, p.Val)
+	}
+
+	return h, nil
+}
+
+// ToMap copies the map's k,v pairs into a new Go map
+func (h *HashMap) ToMap() map[interface{}]interface{} {
+	return h.ToMapInto(make(map[interface{}]interface{}, h.Len()))
+}
+
+// ToMapInto clears dst, fills it with the map's k,v pairs and returns it. Passing the same dst
+// on every call reuses its buckets instead of allocating a new Go map per snapshot. Keys a Go
+// map can't hold are converted the way ToAny converts them, so a []byte key becomes a string.
+func (h *HashMap) ToMapInto(dst map[interface{}]interface{}) map[interface{}]interface{} {
+	clear(dst)
+	h.Each(func(k, v interface{}) {
+		dst[mapKey(k)] = v
+	})
+
+	return dst
+}
+
+// FromMap builds a HashMap from a copy of the Go map's k,v pairs
+func FromMap(m map[interface{}]interface{}) *HashMap {
+	h := NewHashMap()
+	for k, v := range m {
+		h = h.Put(k, v)
+	}
+
+	return h
+}
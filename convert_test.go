@@ -0,0 +1,117 @@
+package immut
+
+import "testing"
+
+func TestVectorListRoundTrip(t *testing.T) {
+	v := NewVector().Put(0, "a").Put(1, "b").Put(2, "c")
+
+	l := v.ToList()
+	if l.String() != "[a, b, c]" {
+		t.Fatalf("Unexpected list %s", l)
+	}
+
+	back := l.ToVector()
+	for i, want := range []string{"a", "b", "c"} {
+		if x, _ := back.Get(i); x != want {
+			t.Errorf("Expected %s at %d got %v", want, i, x)
+		}
+	}
+
+	if NewVector().ToList() != nil {
+		t.Error("Expected the empty vector to convert to the empty list")
+	}
+
+	var empty *List
+	if empty.ToVector().Size() != 0 {
+		t.Error("Expected the empty list to convert to an empty vector")
+	}
+}
+
+func TestHashMapToVector(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 50; i++ {
+		h = h.Put(i, i*2)
+	}
+
+	v := h.ToVector()
+	if v.Size() != 50 {
+		t.Fatalf("Expected 50 got %d", v.Size())
+	}
+
+	back, err := HashMapFromVector(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !back.Equal(h) {
+		t.Error("Expected the round tripped map to equal the original")
+	}
+
+	if _, err := HashMapFromVector(NewVector().Put(0, 1)); err == nil {
+		t.Error("Expected an error for a vector that doesn't hold pairs")
+	}
+}
+
+func TestHashMapKeysVectorList(t *testing.T) {
+	h := NewHashMap().Put("a", 1).Put("b", 2).Put("c", 3)
+
+	keys := h.Keys()
+	kv := h.KeysVector()
+	kl := h.KeysList()
+	if kv.Size() != 3 || kl.Len() != 3 {
+		t.Fatalf("Expected 3 keys got %d and %d", kv.Size(), kl.Len())
+	}
+
+	for i, k := range keys {
+		if x, _ := kv.Get(i); x != k {
+			t.Errorf("Expected %v at %d got %v", k, i, x)
+		}
+
+		if x, _ := kl.Index(i); x != k {
+			t.Errorf("Expected %v at %d got %v", k, i, x)
+		}
+	}
+}
+
+func TestListReverse(t *testing.T) {
+	l := ListFromSlice([]interface{}{1, 2, 3}).Reverse()
+	if x, _ := l.Index(0); x != 3 {
+		t.Errorf("Expected 3 got %v", x)
+	}
+
+	var empty *List
+	if empty.Reverse() != nil {
+		t.Error("Expected the reverse of the empty list to be empty")
+	}
+}
+
+func TestHashMapToMap(t *testing.T) {
+	h := NewHashMap().Put("a", 1).Put(2, "b")
+
+	m := h.ToMap()
+	if len(m) != 2 || m["a"] != 1 || m[2] != "b" {
+		t.Fatalf("Unexpected map %v", m)
+	}
+
+	if m := NewHashMap().Put([]byte("k"), 1).ToMap(); len(m) != 1 || m["k"] != 1 {
+		t.Errorf("Expected a []byte key to become a string, got %v", m)
+	}
+
+	dst := map[interface{}]interface{}{"stale": true}
+	if out := NewHashMap().Put("c", 3).ToMapInto(dst); len(out) != 1 || dst["c"] != 3 {
+		t.Errorf("Expected dst to be cleared and refilled, got %v", dst)
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	m := map[interface{}]interface{}{"a": 1, "b": 2}
+
+	h := FromMap(m)
+	if h.Len() != 2 || len(m) != 2 {
+		t.Fatalf("Expected 2 and 2 got %d and %d", h.Len(), len(m))
+	}
+
+	if v, _ := h.Get("b"); v != 2 {
+		t.Errorf("Expected 2 got %v", v)
+	}
+}
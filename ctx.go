@@ -0,0 +1,156 @@
+package immut
+
+import (
+	"bufio"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ctxCheckEvery is how many entries or nodes are visited between looks at a context
+const ctxCheckEvery = 1024
+
+// ctxCheck tells a long walk over a trie when its context is done. Looking at the context on
+// every step would cost more than the step, so it only looks every ctxCheckEvery steps.
+type ctxCheck struct {
+	ctx context.Context
+	n   int
+	err error
+}
+
+// newCtxCheck returns a ctxCheck that looks at ctx on the first step
+func newCtxCheck(ctx context.Context) *ctxCheck {
+	return &ctxCheck{
+		ctx: ctx,
+		n:   ctxCheckEvery,
+	}
+}
+
+// stop counts a step and returns true once the context is done
+func (c *ctxCheck) stop() bool {
+	if c.err != nil {
+		return true
+	}
+
+	c.n++
+	if c.n >= ctxCheckEvery {
+		c.n = 0
+		c.err = c.ctx.Err()
+	}
+
+	return c.err != nil
+}
+
+// FilterCtx returns a map with only the k,v pairs pred returns true for like RetainWhere, but
+// gives up once ctx is done. It then returns the context's error along with a partial result,
+// in which the pairs visited so far are filtered and the rest are kept as they were.
+func (h *HashMap) FilterCtx(ctx context.Context, pred func(k, v interface{}) bool) (*HashMap, error) {
+	c := newCtxCheck(ctx)
+	n := h.t.root.deleteWhere(func(e Entry) bool {
+		if c.stop() {
+			return false
+		}
+
+		p := e.value.(Pair)
+		return !pred(p.Key, p.Val)
+	}, c.stop)
+
+	if n == h.t.root {
+		return h, c.err
+	}
+
+	return &HashMap{
+		t: &Trie{
+			root:   n,
+			hasher: h.t.hasher,
+		},
+	}, c.err
+}
+
+// DiffCtx returns the changes that turn h into o like Diff, but gives up once ctx is done and
+// returns the context's error. A diff cut short would report keys it hasn't reached in the
+// other map as added or removed, so no partial result is returned.
+func (h *HashMap) DiffCtx(ctx context.Context, o *HashMap) ([]Change, error) {
+	c := newCtxCheck(ctx)
+	changes := h.diff(o, c.stop)
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return changes, nil
+}
+
+// EncodeToCtx writes the map to w as a stream that DecodeFromCtx reads back, one pair at a
+// time, so a large map is never held in memory twice. It stops once ctx is done and returns
+// the context's error, leaving a truncated stream in w that DecodeFromCtx rejects.
+//
+// The stream is
+//
+//	byte 0: format version, currently gobVersion
+//	rest:   a gob stream of the number of pairs as an int, followed by every Pair
+func (h *HashMap) EncodeToCtx(ctx context.Context, w io.Writer) error {
+	b := bufio.NewWriter(w)
+	b.WriteByte(gobVersion)
+
+	enc := gob.NewEncoder(b)
+	if err := enc.Encode(h.Len()); err != nil {
+		return err
+	}
+
+	c := newCtxCheck(ctx)
+	var err error
+	h.t.root.eachUntil(func(e Entry) bool {
+		if c.stop() {
+			err = c.err
+			return false
+		}
+
+		err = enc.Encode(e.value.(Pair))
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return b.Flush()
+}
+
+// DecodeFromCtx reads a map written by EncodeToCtx from r. It stops once ctx is done and
+// returns the context's error.
+func DecodeFromCtx(ctx context.Context, r io.Reader) (*HashMap, error) {
+	b := bufio.NewReader(r)
+	v, err := b.ReadByte()
+	if err != nil {
+		return nil, UnsupportedVersion
+	}
+	if v != gobVersion {
+		return nil, fmt.Errorf("%w: %d", UnsupportedVersion, v)
+	}
+
+	dec := gob.NewDecoder(b)
+	var count int
+	if err := dec.Decode(&count); err != nil {
+		return nil, err
+	}
+
+	c := newCtxCheck(ctx)
+	h := NewHashMap()
+	for i := 0; i < count; i++ {
+		if c.stop() {
+			return nil, c.err
+		}
+
+		var p Pair
+		if err := dec.Decode(&p); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		h = h.Put(p.Key, p.Val)
+	}
+
+	return h, nil
+}
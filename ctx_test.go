@@ -0,0 +1,106 @@
+package immut
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func ctxTestMap(n int) *HashMap {
+	h := NewHashMap()
+	for i := 0; i < n; i++ {
+		h = h.Put(i, i)
+	}
+
+	return h
+}
+
+func TestFilterCtx(t *testing.T) {
+	h := ctxTestMap(10000)
+	even := func(k, v interface{}) bool {
+		return k.(int)%2 == 0
+	}
+
+	n, err := h.FilterCtx(context.Background(), even)
+	if err != nil || n.Len() != 5000 || !n.Equal(h.RetainWhere(even)) {
+		t.Fatalf("Expected the even half got %d pairs and %v", n.Len(), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	visited := 0
+	n, err = h.FilterCtx(ctx, func(k, v interface{}) bool {
+		visited++
+		if visited == 3000 {
+			cancel()
+		}
+		return even(k, v)
+	})
+
+	if err != context.Canceled {
+		t.Fatalf("Expected context.Canceled got %v", err)
+	}
+
+	if n.Len() <= 5000 || n.Len() >= 10000 || visited >= 10000 {
+		t.Errorf("Expected a partly filtered map, got %d pairs after %d visits", n.Len(), visited)
+	}
+
+	h.Each(func(k, v interface{}) {
+		if _, found := n.Get(k); !found && even(k, v) {
+			t.Fatalf("Expected %v to be kept", k)
+		}
+	})
+}
+
+func TestDiffCtx(t *testing.T) {
+	a := ctxTestMap(5000)
+	b := a.Put(1, "x").Put(-1, -1)
+	b, _ = b.Del(2)
+
+	changes, err := a.DiffCtx(context.Background(), b)
+	if err != nil || len(changes) != 3 {
+		t.Fatalf("Expected 3 changes got %v %v", changes, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if changes, err := a.DiffCtx(ctx, NewHashMap()); err != context.Canceled || changes != nil {
+		t.Errorf("Expected context.Canceled and no changes got %d %v", len(changes), err)
+	}
+
+	if changes, err := a.DiffCtx(ctx, NewHashMapWithHasher(FNV32a)); err != context.Canceled || changes != nil {
+		t.Errorf("Expected context.Canceled and no changes got %d %v", len(changes), err)
+	}
+}
+
+func TestEncodeToCtx(t *testing.T) {
+	h := ctxTestMap(5000).Put("a", "b")
+
+	b := bytes.NewBuffer(nil)
+	if err := h.EncodeToCtx(context.Background(), b); err != nil {
+		t.Fatal(err)
+	}
+	data := b.Bytes()
+
+	n, err := DecodeFromCtx(context.Background(), bytes.NewReader(data))
+	if err != nil || !n.Equal(h) {
+		t.Fatalf("Expected the decoded map to match, got %d pairs and %v", n.Len(), err)
+	}
+
+	if _, err := DecodeFromCtx(context.Background(), bytes.NewReader(data[:len(data)/2])); err == nil {
+		t.Error("Expected a truncated stream to fail")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := h.EncodeToCtx(ctx, bytes.NewBuffer(nil)); err != context.Canceled {
+		t.Errorf("Expected context.Canceled got %v", err)
+	}
+
+	if _, err := DecodeFromCtx(ctx, bytes.NewReader(data)); err != context.Canceled {
+		t.Errorf("Expected context.Canceled got %v", err)
+	}
+
+	if _, err := DecodeFromCtx(context.Background(), bytes.NewReader([]byte{9})); err == nil {
+		t.Error("Expected an unknown version to fail")
+	}
+}
@@ -0,0 +1,94 @@
+package immut
+
+// DeleteWhere returns a trie without the k,v pairs pred returns true for. Subtrees without a
+// match are reused as is and only the paths down to removed entries are copied, so dropping a
+// few entries from a large trie costs little more than walking it. The trie itself is returned
+// if nothing matched.
+func (t *Trie) DeleteWhere(pred func([]byte, interface{}) bool) *Trie {
+	n := t.root.deleteWhere(func(e Entry) bool {
+		return pred(e.rawKey, e.value)
+	}, nil)
+	if n == t.root {
+		return t
+	}
+
+	return &Trie{
+		root:   n,
+		hasher: t.hasher,
+	}
+}
+
+// DeleteWhere returns a map without the k,v pairs pred returns true for, sharing everything
+// it doesn't have to change like Trie.DeleteWhere
+func (h *HashMap) DeleteWhere(pred func(k, v interface{}) bool) *HashMap {
+	t := h.t.DeleteWhere(func(_ []byte, v interface{}) bool {
+		p := v.(Pair)
+		return pred(p.Key, p.Val)
+	})
+	if t == h.t {
+		return h
+	}
+
+	return &HashMap{
+		t: t,
+	}
+}
+
+// RetainWhere returns a map with only the k,v pairs pred returns true for, the inverse of
+// DeleteWhere
+func (h *HashMap) RetainWhere(pred func(k, v interface{}) bool) *HashMap {
+	return h.DeleteWhere(func(k, v interface{}) bool {
+		return !pred(k, v)
+	})
+}
+
+// deleteWhere returns the node without the entries pred matches, or t if there were none. If
+// stop is set and returns true the subtrees not visited yet are kept as they are.
+func (t *TNode) deleteWhere(pred func(Entry) bool, stop func() bool) *TNode {
+	y := t
+	for i, e := range t.vals {
+		if !pred(e) {
+			continue
+		}
+
+		// copy the entries around the first match, the old slice is still shared
+		vals := append(make([]Entry, 0, len(t.vals)-1), t.vals[:i]...)
+		for _, e := range t.vals[i+1:] {
+			if !pred(e) {
+				vals = append(vals, e)
+			}
+		}
+
+		y = t.copy()
+		y.vals = vals
+		y.size -= len(t.vals) - len(vals)
+		break
+	}
+
+	for i, c := range t.children {
+		if c == nil {
+			continue
+		}
+		if stop != nil && stop() {
+			break
+		}
+
+		n := c.deleteWhere(pred, stop)
+		if n == c {
+			continue
+		}
+
+		if y == t {
+			y = t.copy()
+		}
+		y.size -= c.size - n.size
+
+		// drop subtrees that no longer hold anything
+		if n.size == 0 {
+			n = nil
+		}
+		y.children[i] = n
+	}
+
+	return y
+}
@@ -0,0 +1,75 @@
+package immut
+
+import "testing"
+
+func TestTrieDeleteWhere(t *testing.T) {
+	x := NewTrie()
+	keys := randBytes(1000)
+	for _, k := range keys {
+		x = x.Put(k, len(k))
+	}
+
+	odd := func(_ []byte, v interface{}) bool {
+		return v.(int)%2 == 1
+	}
+
+	n := x.DeleteWhere(odd)
+	want := x.Size()
+	x.Each(func(k []byte, v interface{}) {
+		if odd(k, v) {
+			want--
+		}
+	})
+
+	if n.Size() != want {
+		t.Errorf("Expected %d got %d", want, n.Size())
+	}
+
+	checkCounts(t, n.root)
+
+	n.Each(func(k []byte, v interface{}) {
+		if odd(k, v) {
+			t.Fatalf("Expected %s to be deleted", k)
+		}
+	})
+
+	if x.DeleteWhere(func([]byte, interface{}) bool { return false }) != x {
+		t.Error("Expected the trie itself back when nothing matches")
+	}
+
+	if x.DeleteWhere(func([]byte, interface{}) bool { return true }).Size() != 0 {
+		t.Error("Expected an empty trie when everything matches")
+	}
+}
+
+func TestHashMapDeleteWhere(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 1000; i++ {
+		h = h.Put(i, i)
+	}
+
+	n := h.DeleteWhere(func(k, _ interface{}) bool {
+		return k.(int) == 500
+	})
+
+	if n.Len() != 999 {
+		t.Errorf("Expected 999 got %d", n.Len())
+	}
+
+	if _, found := n.Get(500); found {
+		t.Error("Expected 500 to be deleted")
+	}
+
+	shared, total := Shared(h, n)
+	if total-shared > maxDepth+1 {
+		t.Errorf("Expected only the path to 500 to be copied, got %d of %d shared", shared, total)
+	}
+
+	r := h.RetainWhere(func(k, _ interface{}) bool {
+		return k.(int) < 10
+	})
+
+	if r.Len() != 10 {
+		t.Errorf("Expected 10 got %d", r.Len())
+	}
+}
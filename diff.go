@@ -0,0 +1,188 @@
+package immut
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+)
+
+// ChangeKind says how a key differs between two maps
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota
+	Removed
+	Modified
+)
+
+// String returns the name of the kind of change
+func (c ChangeKind) String() string {
+	switch c {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+
+	return "unknown"
+}
+
+// A Change is a single key that differs between two maps. Old is unset for Added keys and New
+// is unset for Removed keys.
+type Change struct {
+	Kind ChangeKind
+	Key  interface{}
+	Old  interface{}
+	New  interface{}
+}
+
+// byHash returns the map's entries sorted by the hash of their key, ties broken by the raw key.
+// The order only depends on the keys in the map, not on the order they were inserted in.
+func (h *HashMap) byHash() []Entry {
+	entries := make([]Entry, 0, h.Len())
+	h.t.root.eachEntry(func(e Entry) {
+		entries = append(entries, e)
+	})
+
+	sortEntries(entries)
+	return entries
+}
+
+// sortEntries sorts entries with entryLess
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entryLess(entries[i], entries[j])
+	})
+}
+
+// entryLess orders entries by hash, then by raw key
+func entryLess(a, b Entry) bool {
+	if a.hashedKey != b.hashedKey {
+		return a.hashedKey < b.hashedKey
+	}
+
+	return bytes.Compare(a.rawKey, b.rawKey) < 0
+}
+
+// EachOrderedByHash runs f on each k,v pair in order of the hash of their keys. Unlike Each, the
+// order is the same for any two maps holding the same keys with the same Hasher, however they
+// were built.
+func (h *HashMap) EachOrderedByHash(f func(k, v interface{})) {
+	for _, e := range h.byHash() {
+		p := e.value.(Pair)
+		f(p.Key, p.Val)
+	}
+}
+
+// Diff returns the changes that turn h into o, ordered by the hash of their keys. Values are
+// compared with reflect.DeepEqual. Subtrees the two maps share are skipped without looking at
+// them, so diffing two versions of a large map costs about as much as the entries that changed.
+func (h *HashMap) Diff(o *HashMap) []Change {
+	return h.diff(o, nil)
+}
+
+// diff is Diff, giving up and returning nil as soon as stop returns true if stop is set
+func (h *HashMap) diff(o *HashMap, stop func() bool) []Change {
+	var changes []Change
+	var a, b []Entry
+	if h.t.sameHasher(o.t) {
+		if !h.t.root.unshared(o.t.root, &a, &b, stop) {
+			return nil
+		}
+	} else {
+
+		// hashes from different hashers can't be compared, rehash o's keys the way h does
+		if !h.t.root.eachUntil(func(e Entry) bool {
+			a = append(a, e)
+			return stop == nil || !stop()
+		}) {
+			return nil
+		}
+		if !o.t.root.eachUntil(func(e Entry) bool {
+			b = append(b, h.t.entry(e.rawKey, e.value))
+			return stop == nil || !stop()
+		}) {
+			return nil
+		}
+	}
+
+	sortEntries(a)
+	sortEntries(b)
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		if stop != nil && stop() {
+			return nil
+		}
+
+		switch {
+		case j >= len(b) || (i < len(a) && entryLess(a[i], b[j])):
+			p := a[i].value.(Pair)
+			changes = append(changes, Change{Kind: Removed, Key: p.Key, Old: p.Val})
+			i++
+		case i >= len(a) || entryLess(b[j], a[i]):
+			p := b[j].value.(Pair)
+			changes = append(changes, Change{Kind: Added, Key: p.Key, New: p.Val})
+			j++
+		default:
+			pa := a[i].value.(Pair)
+			pb := b[j].value.(Pair)
+			if !reflect.DeepEqual(pa.Val, pb.Val) {
+				changes = append(changes, Change{Kind: Modified, Key: pb.Key, Old: pa.Val, New: pb.Val})
+			}
+			i++
+			j++
+		}
+	}
+
+	return changes
+}
+
+// unshared collects the entries of t into mine and those of o into theirs, skipping every
+// subtree the two have in common. A shared subtree holds the same entries in both tries and
+// keys are unique within a trie, so none of the skipped keys can show up anywhere else. It
+// returns false if stop is set and returned true before everything was collected.
+func (t *TNode) unshared(o *TNode, mine, theirs *[]Entry, stop func() bool) bool {
+	if t == o {
+		return true
+	}
+
+	if stop != nil && stop() {
+		return false
+	}
+
+	collect := func(dst *[]Entry) func(Entry) bool {
+		return func(e Entry) bool {
+			*dst = append(*dst, e)
+			return stop == nil || !stop()
+		}
+	}
+
+	if t == nil {
+		return o.eachUntil(collect(theirs))
+	}
+
+	if o == nil {
+		return t.eachUntil(collect(mine))
+	}
+
+	*mine = append(*mine, t.vals...)
+	*theirs = append(*theirs, o.vals...)
+	for i := range t.children {
+		if !t.children[i].unshared(o.children[i], mine, theirs, stop) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Equal returns true if both maps hold the same keys mapped to deeply equal values
+func (h *HashMap) Equal(o *HashMap) bool {
+	if h.Len() != o.Len() {
+		return false
+	}
+
+	return len(h.Diff(o)) == 0
+}
@@ -0,0 +1,70 @@
+package immut
+
+import "testing"
+
+func TestEachOrderedByHash(t *testing.T) {
+	a := NewHashMap()
+	b := NewHashMap()
+	for i := 0; i < 500; i++ {
+		a = a.Put(i, i)
+		b = b.Put(499-i, 499-i)
+	}
+
+	var ka, kb []interface{}
+	a.EachOrderedByHash(func(k, v interface{}) {
+		ka = append(ka, k)
+	})
+	b.EachOrderedByHash(func(k, v interface{}) {
+		kb = append(kb, k)
+	})
+
+	if len(ka) != 500 || len(kb) != 500 {
+		t.Fatalf("Expected 500 keys got %d and %d", len(ka), len(kb))
+	}
+
+	for i := range ka {
+		if ka[i] != kb[i] {
+			t.Fatalf("Order depends on insertion order at %d: %v != %v", i, ka[i], kb[i])
+		}
+	}
+}
+
+func TestHashMapDiff(t *testing.T) {
+	a := NewHashMap().Put("same", 1).Put("changed", 2).Put("removed", 3)
+	b := NewHashMap().Put("changed", 20).Put("added", 4).Put("same", 1)
+
+	changes := a.Diff(b)
+	if len(changes) != 3 {
+		t.Fatalf("Expected 3 changes got %v", changes)
+	}
+
+	byKey := map[interface{}]Change{}
+	for _, c := range changes {
+		byKey[c.Key] = c
+	}
+
+	if c := byKey["changed"]; c.Kind != Modified || c.Old != 2 || c.New != 20 {
+		t.Errorf("Unexpected change %+v", c)
+	}
+
+	if c := byKey["removed"]; c.Kind != Removed || c.Old != 3 {
+		t.Errorf("Unexpected change %+v", c)
+	}
+
+	if c := byKey["added"]; c.Kind != Added || c.New != 4 {
+		t.Errorf("Unexpected change %+v", c)
+	}
+}
+
+func TestHashMapEqual(t *testing.T) {
+	a := NewHashMap().Put("a", []int{1}).Put("b", 2)
+	b := NewHashMap().Put("b", 2).Put("a", []int{1})
+
+	if !a.Equal(b) || !a.Equal(a) {
+		t.Error("Expected maps to be equal")
+	}
+
+	if a.Equal(b.Put("b", 3)) || a.Equal(b.Put("c", 3)) {
+		t.Error("Expected maps to differ")
+	}
+}
@@ -0,0 +1,111 @@
+package immut
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ANSI colors used by DiffstringColor
+const (
+	colorAdded    = "\x1b[32m"
+	colorRemoved  = "\x1b[31m"
+	colorModified = "\x1b[33m"
+	colorReset    = "\x1b[0m"
+)
+
+// Diffstring returns a readable diff of the changes that turn a into b, one line per changed
+// key sorted by key: "+k: v" for added keys, "-k: v" for removed keys and "~k: old -> new" for
+// modified ones. Only changed keys are printed, so it stays short for large maps that barely
+// differ. The output only depends on the contents of the maps, which makes it safe to compare
+// in tests.
+func Diffstring(a, b *HashMap) string {
+	return diffstring(a, b, false)
+}
+
+// DiffstringColor returns the same diff as Diffstring with the lines colored for a terminal
+func DiffstringColor(a, b *HashMap) string {
+	return diffstring(a, b, true)
+}
+
+func diffstring(a, b *HashMap, color bool) string {
+
+	// diffLine is a line of the diff and the formatted key it is sorted by
+	type diffLine struct {
+		key  string
+		line string
+	}
+
+	changes := a.Diff(b)
+	lines := make([]diffLine, len(changes))
+	for i, c := range changes {
+		k := formatValue(c.Key)
+
+		var line, start string
+		switch c.Kind {
+		case Added:
+			line = fmt.Sprintf("+%s: %s", k, formatValue(c.New))
+			start = colorAdded
+		case Removed:
+			line = fmt.Sprintf("-%s: %s", k, formatValue(c.Old))
+			start = colorRemoved
+		default:
+			line = fmt.Sprintf("~%s: %s -> %s", k, formatValue(c.Old), formatValue(c.New))
+			start = colorModified
+		}
+
+		if color {
+			line = start + line + colorReset
+		}
+		lines[i] = diffLine{key: k, line: line}
+	}
+
+	// distinct keys can format the same, break ties on the whole line so the order never
+	// depends on the order Diff found them in
+	sort.SliceStable(lines, func(i, j int) bool {
+		if lines[i].key != lines[j].key {
+			return lines[i].key < lines[j].key
+		}
+		return lines[i].line < lines[j].line
+	})
+
+	out := bytes.NewBuffer(nil)
+	for _, l := range lines {
+		out.WriteString(l.line)
+		out.WriteString("\n")
+	}
+
+	return out.String()
+}
+
+// formatValue formats a key or value for a diff. Nested maps and vectors are printed by their
+// contents, with map keys sorted, instead of by their address.
+func formatValue(v interface{}) string {
+	switch x := v.(type) {
+	case *HashMap:
+		pairs := make([]string, 0, x.Len())
+		x.Each(func(k, v interface{}) {
+			pairs = append(pairs, formatValue(k)+": "+formatValue(v))
+		})
+		sort.Strings(pairs)
+		return "{" + strings.Join(pairs, ", ") + "}"
+
+	case *Vector:
+		vals := x.values()
+		parts := make([]string, len(vals))
+		for i, v := range vals {
+			parts[i] = formatValue(v)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+
+	case *List:
+		parts := make([]string, 0, x.Len())
+		x.Each(func(v interface{}) {
+			parts = append(parts, formatValue(v))
+		})
+		return "[" + strings.Join(parts, ", ") + "]"
+	}
+
+	return fmt.Sprintf("%#v", v)
+}
@@ -0,0 +1,46 @@
+package immut
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffstring(t *testing.T) {
+	a := NewHashMap().Put("a", 1).Put("b", 2).Put("c", 3)
+	b := NewHashMap().Put("a", 1).Put("b", 20).Put("d", NewHashMap().Put("y", 2).Put("x", 1))
+
+	got := Diffstring(a, b)
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines got %q", got)
+	}
+
+	expect := []string{`~"b": 2 -> 20`, `-"c": 3`, `+"d": {"x": 1, "y": 2}`}
+	for i, l := range lines {
+		if l != expect[i] {
+			t.Errorf("Expected %q got %q", expect[i], l)
+		}
+	}
+
+	if Diffstring(a, a) != "" {
+		t.Error("Expected no output for equal maps")
+	}
+
+	if c := DiffstringColor(a, b); !strings.Contains(c, colorAdded) || !strings.Contains(c, colorReset) {
+		t.Errorf("Expected colored output got %q", c)
+	}
+}
+
+func TestDiffstringSameFormattedKey(t *testing.T) {
+
+	// 1 and 1.0 are different keys that both format as 1
+	want := "+1: \"a\"\n+1: \"b\"\n"
+	for _, b := range []*HashMap{
+		NewHashMap().Put(1, "a").Put(1.0, "b"),
+		NewHashMap().Put(1, "b").Put(1.0, "a"),
+	} {
+		if got := Diffstring(NewHashMap(), b); got != want {
+			t.Errorf("Expected %q got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package immut
+
+import "time"
+
+// An ExpiringMap is a HashMap whose entries carry a deadline. Entries past their deadline are
+// invisible to Get and are pruned by Expire, which pops keys off a persistent heap ordered by
+// deadline so it only ever touches the entries it removes.
+type ExpiringMap struct {
+	entries *HashMap
+
+	// expiry is the same leftist heap IndexedHeap uses, of keys by deadline. It is never
+	// rewritten when a key is overwritten or deleted, the stale nodes are skipped by Expire and
+	// dropped by compact.
+	expiry *heapNode[interface{}]
+	nodes  int
+	seq    uint64
+}
+
+// expiringEntry is the value stored in the underlying HashMap for every key. seq matches the
+// key's current node in the expiry heap.
+type expiringEntry struct {
+	val      interface{}
+	deadline time.Time
+	seq      uint64
+}
+
+// NewExpiringMap returns an empty ExpiringMap
+func NewExpiringMap() *ExpiringMap {
+	return &ExpiringMap{
+		entries: NewHashMap(),
+	}
+}
+
+// Len returns the number of entries in the map, including expired ones that have not been
+// pruned by Expire yet
+func (e *ExpiringMap) Len() int {
+	return e.entries.Len()
+}
+
+// Set stores v at k until the given deadline
+func (e *ExpiringMap) Set(k, v interface{}, deadline time.Time) *ExpiringMap {
+	n := &ExpiringMap{
+		entries: e.entries.Put(k, expiringEntry{val: v, deadline: deadline, seq: e.seq}),
+		expiry:  e.expiry.merge(expiryNode(k, deadline, e.seq)),
+		nodes:   e.nodes + 1,
+		seq:     e.seq + 1,
+	}
+	n.compact()
+
+	return n
+}
+
+// SetTTL stores v at k for ttl from now
+func (e *ExpiringMap) SetTTL(k, v interface{}, ttl time.Duration) *ExpiringMap {
+	return e.Set(k, v, time.Now().Add(ttl))
+}
+
+// Get returns the value stored at k if it has not expired at now
+func (e *ExpiringMap) Get(k interface{}, now time.Time) (interface{}, bool) {
+	x, found := e.entries.Get(k)
+	if !found || expired(x.(expiringEntry).deadline, now) {
+		return nil, false
+	}
+
+	return x.(expiringEntry).val, true
+}
+
+// Deadline returns the deadline of the entry stored at k
+func (e *ExpiringMap) Deadline(k interface{}) (time.Time, bool) {
+	x, found := e.entries.Get(k)
+	if !found {
+		return time.Time{}, false
+	}
+
+	return x.(expiringEntry).deadline, true
+}
+
+// Del removes the entry stored at k
+func (e *ExpiringMap) Del(k interface{}) *ExpiringMap {
+	entries, _ := e.entries.Del(k)
+	n := &ExpiringMap{
+		entries: entries,
+		expiry:  e.expiry,
+		nodes:   e.nodes,
+		seq:     e.seq,
+	}
+	n.compact()
+
+	return n
+}
+
+// Each runs f on every k,v pair that has not expired at now
+func (e *ExpiringMap) Each(now time.Time, f func(k, v interface{})) {
+	e.entries.Each(func(k, v interface{}) {
+		x := v.(expiringEntry)
+		if !expired(x.deadline, now) {
+			f(k, x.val)
+		}
+	})
+}
+
+// Expire prunes every entry that has expired at now. Only the nodes on the paths the heap
+// merges along are copied, the rest is shared with the previous version.
+func (e *ExpiringMap) Expire(now time.Time) *ExpiringMap {
+	entries := e.entries
+	h := e.expiry
+	nodes := e.nodes
+	for h != nil {
+		x, live := e.live(h)
+		if live && !expired(x.deadline, now) {
+			break
+		}
+
+		if live {
+			entries, _ = entries.Del(h.key)
+		}
+		h = h.left.merge(h.right)
+		nodes--
+	}
+
+	if h == e.expiry {
+		return e
+	}
+
+	return &ExpiringMap{
+		entries: entries,
+		expiry:  h,
+		nodes:   nodes,
+		seq:     e.seq,
+	}
+}
+
+// live returns the entry for n's key if n is that key's current node in the expiry heap
+func (e *ExpiringMap) live(n *heapNode[interface{}]) (expiringEntry, bool) {
+	v, found := e.entries.Get(n.key)
+	if !found || v.(expiringEntry).seq != n.seq {
+		return expiringEntry{}, false
+	}
+
+	return v.(expiringEntry), true
+}
+
+// compact rebuilds the expiry heap from the entries once stale nodes outnumber the live ones,
+// so overwrites and deletes can't grow it without bound. It may only be called on a map nobody
+// else has seen yet.
+func (e *ExpiringMap) compact() {
+	if e.nodes <= 2*e.Len()+8 {
+		return
+	}
+
+	e.expiry = nil
+	e.nodes = 0
+	e.entries.Each(func(k, v interface{}) {
+		x := v.(expiringEntry)
+		e.expiry = e.expiry.merge(expiryNode(k, x.deadline, x.seq))
+		e.nodes++
+	})
+}
+
+// expiryNode returns a heap node for k ordered by its deadline, then by when it was set
+func expiryNode(k interface{}, deadline time.Time, seq uint64) *heapNode[interface{}] {
+	return &heapNode[interface{}]{
+		key:      k,
+		priority: deadline.UnixNano(),
+		seq:      seq,
+		rank:     1,
+	}
+}
+
+// expired returns true if the deadline has passed at now
+func expired(deadline, now time.Time) bool {
+	return !now.Before(deadline)
+}
@@ -0,0 +1,85 @@
+package immut
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiringMapGet(t *testing.T) {
+	now := time.Unix(1000, 0)
+	e := NewExpiringMap().Set("a", 1, now.Add(time.Second))
+
+	if v, found := e.Get("a", now); !found || v != 1 {
+		t.Errorf("Expected 1 got %v", v)
+	}
+
+	if _, found := e.Get("a", now.Add(time.Second)); found {
+		t.Error("a should have expired")
+	}
+}
+
+func TestExpiringMapExpire(t *testing.T) {
+	now := time.Unix(1000, 0)
+	e := NewExpiringMap()
+	for i := 0; i < 10; i++ {
+		e = e.Set(i, i, now.Add(time.Duration(10-i)*time.Second))
+	}
+
+	// push 9 back so its first index entry is stale
+	e = e.Set(9, 9, now.Add(time.Hour))
+
+	x := e.Expire(now.Add(5 * time.Second))
+	if x.Len() != 6 {
+		t.Errorf("Expected 6 got %d", x.Len())
+	}
+
+	for i := 0; i < 10; i++ {
+		_, found := x.Deadline(i)
+		if want := i < 5 || i == 9; found != want {
+			t.Errorf("Expected %d found to be %t", i, want)
+		}
+	}
+
+	if e.Len() != 10 {
+		t.Errorf("Expire should not modify the previous version")
+	}
+
+	if y := x.Expire(now.Add(5 * time.Second)); y != x {
+		t.Error("Expire with nothing to prune should return the same map")
+	}
+}
+
+func TestExpiringMapSetInOrder(t *testing.T) {
+	now := time.Unix(1000, 0)
+	e := NewExpiringMap()
+	for i := 0; i < 1000; i++ {
+		e = e.Set(i%100, i, now.Add(time.Duration(i)*time.Second))
+	}
+
+	if e.Len() != 100 || e.nodes > 2*e.Len()+8 {
+		t.Errorf("Expected stale index nodes to be dropped, have %d for %d entries", e.nodes, e.Len())
+	}
+
+	x := e.Expire(now.Add(950 * time.Second))
+	if x.Len() != 49 {
+		t.Errorf("Expected 49 got %d", x.Len())
+	}
+
+	for i := 0; i < 100; i++ {
+		if _, found := x.Deadline(i); found != (i > 50) {
+			t.Errorf("Expected %d found to be %t", i, i > 50)
+		}
+	}
+}
+
+func TestExpiringMapDelCompacts(t *testing.T) {
+	far := time.Unix(1000, 0).Add(24 * time.Hour)
+	e := NewExpiringMap()
+	for i := 0; i < 1000; i++ {
+		e = e.Set(i, i, far).Del(i)
+	}
+
+	if e.Len() != 0 || e.nodes > 8 {
+		t.Errorf("Expected deleted keys to leave the index, have %d nodes", e.nodes)
+	}
+}
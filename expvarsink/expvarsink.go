@@ -0,0 +1,24 @@
+// Package expvarsink publishes the events immut reports to a MetricsSink as expvar counters.
+// It lives apart from immut so importing immut doesn't pull in expvar, which registers the
+// /debug/vars handler on http.DefaultServeMux.
+package expvarsink
+
+import "expvar"
+
+// Sink is an immut.MetricsSink that adds every event to a counter of the same name in an
+// expvar.Map, e.g. one created with expvar.NewMap("immut") to publish them under /debug/vars
+type Sink struct {
+	m *expvar.Map
+}
+
+// New returns a sink that counts events in m
+func New(m *expvar.Map) *Sink {
+	return &Sink{
+		m: m,
+	}
+}
+
+// Observe implements immut.MetricsSink
+func (s *Sink) Observe(event string, n int) {
+	s.m.Add(event, int64(n))
+}
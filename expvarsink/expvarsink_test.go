@@ -0,0 +1,29 @@
+package expvarsink
+
+import (
+	"expvar"
+	"fmt"
+	"testing"
+
+	"github.com/eliothedeman/immut"
+)
+
+var _ immut.MetricsSink = &Sink{}
+
+func TestSink(t *testing.T) {
+	m := new(expvar.Map).Init()
+	immut.SetMetricsSink(New(m))
+	defer immut.SetMetricsSink(nil)
+
+	x := immut.NewTrie()
+	for i := 0; i < 100; i++ {
+		x = x.Put([]byte(fmt.Sprint(i)), i)
+	}
+
+	for _, event := range []string{immut.EventNodeAlloc, immut.EventPathCopy} {
+		c, ok := m.Get(event).(*expvar.Int)
+		if !ok || c.Value() == 0 {
+			t.Errorf("Expected %s to be counted", event)
+		}
+	}
+}
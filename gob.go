@@ -0,0 +1,148 @@
+package immut
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// The gob wire format of HashMap, Vector, List and Patch is
+//
+//	byte 0: format version, currently gobVersion
+//	rest:   a gob stream holding a single value
+//	        HashMap: []Pair, in no particular order
+//	        Vector:  []Pair, with the int index of every element as its Key
+//	        List:    []interface{}, head first
+//	        Patch:   []PatchOp, in the order they are applied
+//
+// A new version is only added when the layout above changes, and decoders keep accepting every
+// older version, so snapshots written by one release can be read by the next. HashMap also has
+// a streamed form written pair by pair, see EncodeToCtx.
+const gobVersion = 1
+
+var (
+	UnsupportedVersion = errors.New("unsupported gob format version")
+)
+
+// RegisterGob registers the concrete key and value types of a collection with gob. gob has to
+// know every concrete type it meets behind an interface{}, which is how HashMap, Vector and
+// List store everything.
+func RegisterGob[K, V any]() {
+	registerGobType[K]()
+	registerGobType[V]()
+}
+
+// registerGobType registers T with gob unless it is an interface type, which has no concrete
+// value to register
+func registerGobType[T any]() {
+	var t T
+	if reflect.TypeOf(&t).Elem().Kind() == reflect.Interface {
+		return
+	}
+
+	gob.Register(t)
+}
+
+// GobEncode implements gob.GobEncoder
+func (h *HashMap) GobEncode() ([]byte, error) {
+	return encodeGob(h.Entries())
+}
+
+// GobDecode implements gob.GobDecoder
+func (h *HashMap) GobDecode(data []byte) error {
+	var entries []Pair
+	if err := decodeGob(data, &entries); err != nil {
+		return err
+	}
+
+	n := NewHashMap()
+	for _, e := range entries {
+		n = n.Put(e.Key, e.Val)
+	}
+	*h = *n
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder
+func (v *Vector) GobEncode() ([]byte, error) {
+	entries := make([]Pair, 0, v.Size())
+	v.root.eachEntry(func(e Entry) {
+		entries = append(entries, Pair{
+			Key: int(binary.LittleEndian.Uint32(e.rawKey)) - v.offset,
+			Val: e.value,
+		})
+	})
+
+	return encodeGob(entries)
+}
+
+// GobDecode implements gob.GobDecoder
+func (v *Vector) GobDecode(data []byte) error {
+	var entries []Pair
+	if err := decodeGob(data, &entries); err != nil {
+		return err
+	}
+
+	n := NewVector()
+	for _, e := range entries {
+		i, ok := e.Key.(int)
+		if !ok {
+			return fmt.Errorf("immut: vector index %#v is not an int", e.Key)
+		}
+		if i < 0 {
+			return fmt.Errorf("immut: vector index %d is negative", i)
+		}
+		n = n.Put(i, e.Val)
+	}
+	*v = *n
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder
+func (l *List) GobEncode() ([]byte, error) {
+	return encodeGob(l.ToSlice())
+}
+
+// GobDecode implements gob.GobDecoder
+func (l *List) GobDecode(data []byte) error {
+	var vals []interface{}
+	if err := decodeGob(data, &vals); err != nil {
+		return err
+	}
+
+	if len(vals) == 0 {
+		return errors.New("immut: can't decode the empty list into a *List")
+	}
+
+	*l = *ListFromSlice(vals)
+	return nil
+}
+
+// encodeGob writes the version byte followed by the gob encoding of contents
+func encodeGob(contents interface{}) ([]byte, error) {
+	b := bytes.NewBuffer(nil)
+	b.WriteByte(gobVersion)
+	if err := gob.NewEncoder(b).Encode(contents); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeGob checks the version byte and decodes the rest of data into contents
+func decodeGob(data []byte, contents interface{}) error {
+	if len(data) == 0 {
+		return UnsupportedVersion
+	}
+
+	if data[0] != gobVersion {
+		return fmt.Errorf("%w: %d", UnsupportedVersion, data[0])
+	}
+
+	return gob.NewDecoder(bytes.NewReader(data[1:])).Decode(contents)
+}
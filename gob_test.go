@@ -0,0 +1,103 @@
+package immut
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+)
+
+type gobPoint struct {
+	X, Y int
+}
+
+func roundTrip(t *testing.T, in, out interface{}) {
+	t.Helper()
+	b := bytes.NewBuffer(nil)
+	if err := gob.NewEncoder(b).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gob.NewDecoder(b).Decode(out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHashMapGob(t *testing.T) {
+	RegisterGob[string, gobPoint]()
+	RegisterGob[interface{}, *HashMap]()
+
+	inner := NewHashMap().Put("n", 1)
+	h := NewHashMap().Put("a", gobPoint{1, 2}).Put("b", inner)
+
+	out := NewHashMap()
+	roundTrip(t, h, out)
+
+	if v, _ := out.Get("a"); v != (gobPoint{1, 2}) {
+		t.Errorf("Expected {1 2} got %v", v)
+	}
+
+	v, _ := out.Get("b")
+	if n, _ := v.(*HashMap).Get("n"); n != 1 {
+		t.Errorf("Expected 1 got %v", n)
+	}
+}
+
+func TestVectorGob(t *testing.T) {
+	v := NewVector()
+	for i := 0; i < 40; i++ {
+		v = v.Put(i, i*2)
+	}
+
+	out := NewVector()
+	roundTrip(t, v, out)
+
+	if out.Size() != 40 {
+		t.Fatalf("Expected 40 elements got %d", out.Size())
+	}
+
+	for i := 0; i < 40; i++ {
+		if x, _ := out.Get(i); x != i*2 {
+			t.Errorf("Expected %d got %v", i*2, x)
+		}
+	}
+
+	v = NewVector().Put(0, "a").Put(5, "f")
+	out = NewVector()
+	roundTrip(t, v, out)
+
+	if x, _ := out.Get(5); x != "f" || out.Size() != 6 {
+		t.Errorf("Expected f and 6 got %v and %d", x, out.Size())
+	}
+
+	if _, found := out.Get(1); found {
+		t.Error("Sparse indexes should stay empty")
+	}
+}
+
+func TestListGob(t *testing.T) {
+	l := ListFromSlice([]interface{}{1, "two", 3.0})
+
+	out := &List{}
+	roundTrip(t, l, out)
+
+	if out.String() != l.String() {
+		t.Errorf("Expected %s got %s", l, out)
+	}
+}
+
+func TestGobVersion(t *testing.T) {
+	b, err := NewHashMap().Put(1, 1).GobEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b[0] != gobVersion {
+		t.Errorf("Expected version %d got %d", gobVersion, b[0])
+	}
+
+	b[0] = 99
+	if err := NewHashMap().GobDecode(b); !errors.Is(err, UnsupportedVersion) {
+		t.Errorf("Expected %v got %v", UnsupportedVersion, err)
+	}
+}
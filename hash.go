@@ -13,6 +13,7 @@ const (
 	UInt
 	Float
 	String
+	Custom
 )
 
 // Byteser returns the []bytes representation of the type. Note this does not need to be able to
@@ -21,66 +22,116 @@ type Byteser interface {
 	Bytes() []byte
 }
 
-// HashMap maps anything to anything using the immutible trie type
+// HashMap maps anything to anything using the immutible trie type. Every key is stored next to
+// its value in a single trie, so Keys, Values and Each all see the pairs in the same order.
 type HashMap struct {
-	keys *Trie
-	vals *Trie
+	t *Trie
 }
 
 // NewHashMap
 func NewHashMap() *HashMap {
 	return &HashMap{
-		keys: NewTrie(),
-		vals: NewTrie(),
+		t: NewTrie(),
+	}
+}
+
+// NewHashMapWithHasher returns an empty HashMap that hashes its keys with h
+func NewHashMapWithHasher(h Hasher) *HashMap {
+	return &HashMap{
+		t: NewTrieWithHasher(h),
 	}
 }
 
 // Each funs a function on each k,v pair
 func (h *HashMap) Each(f func(k, v interface{})) {
-	keys := h.keys.Values()
+	h.t.Each(func(_ []byte, i interface{}) {
+		p := i.(Pair)
+		f(p.Key, p.Val)
+	})
+}
 
-	for _, k := range keys {
-		v, _ := h.Get(k)
-		f(k, v)
-	}
+// Len returns the number of k,v pairs stored in the hash map
+func (h *HashMap) Len() int {
+	return h.t.Size()
 }
 
 // Keys returns the keys stored in the hash map
 func (h *HashMap) Keys() []interface{} {
-	return h.keys.Values()
+	keys := make([]interface{}, 0, h.Len())
+	h.Each(func(k, _ interface{}) {
+		keys = append(keys, k)
+	})
+
+	return keys
 }
 
 // Values returns the values stored in the has map
 func (h *HashMap) Values() []interface{} {
-	return h.vals.Values()
+	vals := make([]interface{}, 0, h.Len())
+	h.Each(func(_, v interface{}) {
+		vals = append(vals, v)
+	})
+
+	return vals
 }
 
 // Put will map anything to anything in the internal trie
 func (h *HashMap) Put(k, v interface{}) *HashMap {
-	kBytes := iToBytes(k)
-	nKeys := h.keys.Put(kBytes, k)
-	nVals := h.vals.Put(kBytes, v)
+	return &HashMap{
+		t: h.t.Put(iToBytes(k), Pair{Key: k, Val: v}),
+	}
+}
+
+// PutIfAbsent maps k to v only if k is not in the map yet, in a single walk of the trie. It
+// returns true if v was inserted.
+func (h *HashMap) PutIfAbsent(k, v interface{}) (*HashMap, bool) {
+	t, _, inserted := h.t.PutIfAbsent(iToBytes(k), Pair{Key: k, Val: v})
+	if !inserted {
+		return h, false
+	}
 
 	return &HashMap{
-		keys: nKeys,
-		vals: nVals,
+		t: t,
+	}, true
+}
+
+// GetOrPut returns the value stored at k. If there is none, it stores the result of f at k and
+// returns it along with the new map, in a single walk of the trie. f is only called when k is
+// missing.
+func (h *HashMap) GetOrPut(k interface{}, f func() interface{}) (interface{}, *HashMap) {
+	t, p, inserted := h.t.getOrPut(iToBytes(k), func() interface{} {
+		return Pair{Key: k, Val: f()}
+	})
+	if !inserted {
+		return p.(Pair).Val, h
+	}
+
+	return p.(Pair).Val, &HashMap{
+		t: t,
 	}
 }
 
 // Get returns the value stored at the given key if it exists else nil, false
 func (h *HashMap) Get(k interface{}) (interface{}, bool) {
-	return h.vals.Get(iToBytes(k))
+	p, found := h.t.Get(iToBytes(k))
+	if !found {
+		return nil, false
+	}
+
+	return p.(Pair).Val, true
 }
 
 // Del deletes the value stored at the given key
 func (h *HashMap) Del(k interface{}) (*HashMap, interface{}) {
-	kBytes := iToBytes(k)
-	nKeys, _ := h.keys.Del(kBytes)
-	nVals, val := h.vals.Del(kBytes)
+	n, p := h.t.Del(iToBytes(k))
+
+	var val interface{}
+	if p != nil {
+		val = p.(Pair).Val
+	}
 
 	return &HashMap{
-		keys: nKeys,
-		vals: nVals,
+		t: n,
 	}, val
 
 }
@@ -153,6 +204,12 @@ func iToBytes(i interface{}) []byte {
 
 	// handle strings/bytes
 	switch i := i.(type) {
+	case Byteser:
+		b := i.Bytes()
+		x := make([]byte, len(b)+1)
+		x[0] = Custom
+		copy(x[1:], b)
+		return x
 	case string:
 		x := make([]byte, len(i)+1)
 		x[0] = String
@@ -162,7 +219,7 @@ func iToBytes(i interface{}) []byte {
 		x := make([]byte, len(i)+1)
 		x[0] = String
 		copy(x[1:], i)
-		return i
+		return x
 	}
 
 	// handle numbers
@@ -38,6 +38,12 @@ func TestIToBytes(t *testing.T) {
 		{
 			1.0, []byte{Float, 0, 0, 0, 0, 0, 0, 240, 63},
 		},
+		{
+			"hi", []byte{String, 'h', 'i'},
+		},
+		{
+			[]byte("hi"), []byte{String, 'h', 'i'},
+		},
 	}
 
 	for _, test := range tests {
@@ -85,6 +91,48 @@ func TestHashMap(t *testing.T) {
 	})
 }
 
+func TestHashMapKeysValues(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 100; i++ {
+		h = h.Put(i, i*2)
+	}
+
+	keys := h.Keys()
+	vals := h.Values()
+	for i := range keys {
+		if vals[i] != keys[i].(int)*2 {
+			t.Fatalf("Expected %d got %v", keys[i].(int)*2, vals[i])
+		}
+	}
+}
+
+func TestHashMapDel(t *testing.T) {
+	h := NewHashMap().Put("a", 1).Put("b", 2)
+
+	x, v := h.Del("a")
+	if v != 1 {
+		t.Errorf("Expected 1 got %v", v)
+	}
+
+	if x.Len() != 1 || h.Len() != 2 {
+		t.Errorf("Expected 1 and 2 got %d and %d", x.Len(), h.Len())
+	}
+
+	if _, v = x.Del("a"); v != nil {
+		t.Errorf("Expected nil got %v", v)
+	}
+}
+
+func BenchmarkHashMapPut(b *testing.B) {
+	keys := randStrs(1000)
+	h := NewHashMap()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h = h.Put(keys[i%len(keys)], i)
+	}
+}
+
 func BenchmarkHashAnythingStr(b *testing.B) {
 	strs := randStrs(10000)
 	b.ReportAllocs()
@@ -116,3 +164,66 @@ func BenchmarkHashAnythingByter(b *testing.B) {
 		hashAnything(x)
 	}
 }
+
+func TestHashMapPutIfAbsent(t *testing.T) {
+	h := NewHashMap().Put("a", 1)
+
+	x, inserted := h.PutIfAbsent("a", 2)
+	if inserted || x != h {
+		t.Error("Existing key should not be overwritten")
+	}
+
+	if v, _ := x.Get("a"); v != 1 {
+		t.Errorf("Expected 1 got %v", v)
+	}
+
+	x, inserted = h.PutIfAbsent("b", 2)
+	if !inserted || x.Len() != 2 || h.Len() != 1 {
+		t.Errorf("Expected b to be inserted into a new map, got %t, %d, %d", inserted, x.Len(), h.Len())
+	}
+}
+
+func TestHashMapGetOrPut(t *testing.T) {
+	calls := 0
+	f := func() interface{} {
+		calls++
+		return "new"
+	}
+
+	h := NewHashMap()
+	v, h := h.GetOrPut("k", f)
+	if v != "new" || h.Len() != 1 {
+		t.Errorf("Expected new got %v", v)
+	}
+
+	v, _ = h.GetOrPut("k", f)
+	if v != "new" || calls != 1 {
+		t.Errorf("Expected f to be called once got %d", calls)
+	}
+}
+
+func TestHashMapGetOrPutDeep(t *testing.T) {
+
+	// constHasher sends every key down to the bucket at the bottom of the trie
+	for _, h := range []*HashMap{NewHashMap(), NewHashMapWithHasher(constHasher{})} {
+		for i := 0; i < 50; i++ {
+			v, n := h.GetOrPut(i, func() interface{} {
+				return i * 2
+			})
+			if v != i*2 || n.Len() != i+1 {
+				t.Fatalf("Expected %d and %d keys got %v and %d", i*2, i+1, v, n.Len())
+			}
+			h = n
+		}
+
+		for i := 0; i < 50; i++ {
+			v, n := h.GetOrPut(i, func() interface{} {
+				t.Fatalf("Expected f not to be called for %d", i)
+				return nil
+			})
+			if v != i*2 || n != h {
+				t.Errorf("Expected %d from the same map got %v", i*2, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package immut
+
+import (
+	"hash/fnv"
+	"hash/maphash"
+)
+
+// A Hasher hashes keys into the 32 bits a Trie is indexed by. Implementations must be
+// comparable, since tries check whether they share a Hasher before merging or diffing.
+type Hasher interface {
+	Hash(key []byte) uint32
+}
+
+var (
+	// FNV32 hashes keys with 32 bit FNV-1, the default
+	FNV32 Hasher = fnv32{}
+
+	// FNV32a hashes keys with 32 bit FNV-1a
+	FNV32a Hasher = fnv32a{}
+
+	// FNV64a hashes keys with 64 bit FNV-1a folded into 32 bits
+	FNV64a Hasher = fnv64a{}
+)
+
+type fnv32 struct{}
+
+func (fnv32) Hash(key []byte) uint32 {
+	return hashKey(key)
+}
+
+type fnv32a struct{}
+
+func (fnv32a) Hash(key []byte) uint32 {
+	h := fnv.New32a()
+	h.Write(key)
+	return h.Sum32()
+}
+
+type fnv64a struct{}
+
+func (fnv64a) Hash(key []byte) uint32 {
+	h := fnv.New64a()
+	h.Write(key)
+	s := h.Sum64()
+	return uint32(s>>32) ^ uint32(s)
+}
+
+// NewSeededHasher returns a Hasher backed by hash/maphash with the given seed. Without the seed
+// the hashes can't be predicted, which keeps crafted keys from all landing in the same bucket.
+// Tries that are merged or diffed have to use the same seed.
+func NewSeededHasher(seed maphash.Seed) Hasher {
+	return seeded{seed}
+}
+
+type seeded struct {
+	seed maphash.Seed
+}
+
+func (s seeded) Hash(key []byte) uint32 {
+	h := maphash.Bytes(s.seed, key)
+	return uint32(h>>32) ^ uint32(h)
+}
+
+// NewTrieWithHasher returns an empty Trie that hashes its keys with h
+func NewTrieWithHasher(h Hasher) *Trie {
+	t := NewTrie()
+	t.hasher = h
+	return t
+}
+
+// Hasher returns the Hasher the trie hashes its keys with
+func (t *Trie) Hasher() Hasher {
+	if t.hasher == nil {
+		return FNV32
+	}
+
+	return t.hasher
+}
+
+// entry returns the entry for key hashed with the trie's Hasher
+func (t *Trie) entry(key []byte, val interface{}) Entry {
+	if t.hasher == nil {
+		return newEntry(key, val)
+	}
+
+	return Entry{
+		rawKey:    key,
+		hashedKey: t.hasher.Hash(key),
+		value:     val,
+	}
+}
+
+// sameHasher returns true if both tries hash their keys the same way
+func (t *Trie) sameHasher(o *Trie) bool {
+	return t.Hasher() == o.Hasher()
+}
+
+// LevelStats describes the nodes at one depth of a trie
+type LevelStats struct {
+	// Nodes is the number of nodes at this depth
+	Nodes int
+
+	// Entries is the number of entries stored in those nodes
+	Entries int
+
+	// Children is the number of child slots of those nodes that are in use
+	Children int
+}
+
+// Occupancy returns the fraction of the level's child slots that are in use
+func (l LevelStats) Occupancy() float64 {
+	if l.Nodes == 0 {
+		return 0
+	}
+
+	return float64(l.Children) / float64(l.Nodes*width)
+}
+
+// HashStats describes how well a trie's keys are spread out by its Hasher
+type HashStats struct {
+	// Levels holds the stats of every depth, starting at the root
+	Levels []LevelStats
+
+	// Collisions is the number of entries whose full hash is shared with another entry
+	Collisions int
+
+	// MaxBucket is the most entries held by a single node
+	MaxBucket int
+}
+
+// HashStats walks the trie and returns stats on how its keys are spread out, to judge the
+// quality of a Hasher for a set of keys. A good Hasher keeps the trie shallow, its
+// collisions near zero and its buckets small.
+func (t *Trie) HashStats() HashStats {
+	var s HashStats
+	hashes := make(map[uint32]int, t.Size())
+	t.root.eachNode(func(n *TNode) {
+		for int(n.depth) >= len(s.Levels) {
+			s.Levels = append(s.Levels, LevelStats{})
+		}
+
+		l := &s.Levels[n.depth]
+		l.Nodes++
+		l.Entries += len(n.vals)
+		for _, c := range n.children {
+			if c != nil {
+				l.Children++
+			}
+		}
+
+		if len(n.vals) > s.MaxBucket {
+			s.MaxBucket = len(n.vals)
+		}
+
+		for _, e := range n.vals {
+			hashes[e.hashedKey]++
+		}
+	})
+
+	for _, n := range hashes {
+		if n > 1 {
+			s.Collisions += n
+		}
+	}
+
+	return s
+}
+
+// HashStats returns stats on how the map's keys are spread out like Trie.HashStats
+func (h *HashMap) HashStats() HashStats {
+	return h.t.HashStats()
+}
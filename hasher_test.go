@@ -0,0 +1,102 @@
+package immut
+
+import (
+	"fmt"
+	"hash/maphash"
+	"testing"
+)
+
+func TestHashers(t *testing.T) {
+	keys := randBytes(2000)
+	for _, h := range []Hasher{FNV32, FNV32a, FNV64a, NewSeededHasher(maphash.MakeSeed())} {
+		x := NewTrieWithHasher(h)
+		for i, k := range keys {
+			x = x.Put(k, i)
+		}
+
+		if x.Hasher() != h {
+			t.Errorf("Expected the trie to keep its hasher %T", h)
+		}
+
+		for i, k := range keys {
+			if v, found := x.Get(k); !found || v != i {
+				t.Fatalf("%T: expected %d got %v", h, i, v)
+			}
+		}
+
+		d, _ := x.Del(keys[0])
+		if _, found := d.Get(keys[0]); found || d.Size() != x.Size()-1 {
+			t.Errorf("%T: expected the delete to find the key", h)
+		}
+
+		if d.Hasher() != h {
+			t.Errorf("Expected derived tries to keep the hasher %T", h)
+		}
+
+		if merged := MergeShards(x.Shard(2)...); merged.Size() != x.Size() || merged.Hasher() != h {
+			t.Errorf("%T: unexpected merge of %d keys", h, merged.Size())
+		}
+	}
+
+	if NewTrie().Hasher() != FNV32 {
+		t.Error("Expected FNV32 to be the default")
+	}
+}
+
+func TestHashMapWithHasher(t *testing.T) {
+	a := NewHashMap()
+	b := NewHashMapWithHasher(FNV32a)
+	for i := 0; i < 100; i++ {
+		a = a.Put(i, i)
+		b = b.Put(i, i)
+	}
+
+	if !a.Equal(b) {
+		t.Error("Expected maps with different hashers to still compare equal")
+	}
+
+	b = b.Put(5, "five")
+	if d := a.Diff(b); len(d) != 1 || d[0].Key != 5 {
+		t.Errorf("Expected a single change got %v", d)
+	}
+}
+
+func TestHashStats(t *testing.T) {
+	x := NewTrie()
+	for i := 0; i < 1000; i++ {
+		x = x.Put([]byte(fmt.Sprint(i)), i)
+	}
+
+	s := x.HashStats()
+	entries := 0
+	for _, l := range s.Levels {
+		entries += l.Entries
+		if o := l.Occupancy(); o < 0 || o > 1 {
+			t.Errorf("Unexpected occupancy %f", o)
+		}
+	}
+
+	if entries != 1000 {
+		t.Errorf("Expected 1000 entries across the levels got %d", entries)
+	}
+
+	if s.Levels[0].Nodes != 1 || s.MaxBucket < 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+
+	// every key hashing the same piles everything up
+	worst := NewTrieWithHasher(constHasher{})
+	for i := 0; i < 100; i++ {
+		worst = worst.Put([]byte(fmt.Sprint(i)), i)
+	}
+
+	if s := worst.HashStats(); s.Collisions != 100 || s.MaxBucket < 90 {
+		t.Errorf("Expected every key to collide, got %+v", s)
+	}
+}
+
+type constHasher struct{}
+
+func (constHasher) Hash([]byte) uint32 {
+	return 7
+}
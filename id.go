@@ -0,0 +1,36 @@
+package immut
+
+// An ID identifies one version of a Trie, HashMap or Vector. IDs are comparable, so they can be
+// used as Go map keys or checked with ==. Two IDs are equal only if they come from the same
+// version, or from versions that share their whole trie, e.g. after a Del of a missing key. Equal
+// IDs mean equal contents, but different IDs don't mean the contents differ.
+//
+// An ID keeps the version it came from reachable, so it never matches a later version that
+// happens to reuse its memory.
+type ID struct {
+	root   *TNode
+	size   int
+	offset int
+}
+
+// ID returns the identity of this version of the trie
+func (t *Trie) ID() ID {
+	return ID{
+		root: t.root,
+		size: t.root.size,
+	}
+}
+
+// ID returns the identity of this version of the map
+func (h *HashMap) ID() ID {
+	return h.t.ID()
+}
+
+// ID returns the identity of this version of the vector
+func (v *Vector) ID() ID {
+	return ID{
+		root:   v.root,
+		size:   v.size,
+		offset: v.offset,
+	}
+}
@@ -0,0 +1,37 @@
+package immut
+
+import "testing"
+
+func TestHashMapID(t *testing.T) {
+	h := NewHashMap().Put("a", 1)
+
+	if h.ID() != h.ID() {
+		t.Error("Expected the same map to have the same ID")
+	}
+
+	n := h.Put("b", 2)
+	if n.ID() == h.ID() {
+		t.Error("Expected a new version to have a new ID")
+	}
+
+	same, _ := n.Del("missing")
+	if same.ID() != n.ID() {
+		t.Error("Expected deleting a missing key to keep the ID")
+	}
+
+	if NewHashMap().Put("a", 1).ID() == h.ID() {
+		t.Error("Expected separately built maps to have different IDs")
+	}
+
+	seen := map[ID]bool{h.ID(): true}
+	if !seen[h.ID()] || seen[n.ID()] {
+		t.Error("Expected IDs to work as map keys")
+	}
+}
+
+func TestVectorID(t *testing.T) {
+	v := NewVector().Put(0, "a")
+	if v.ID() != v.ID() || v.Put(1, "b").ID() == v.ID() {
+		t.Error("Expected a new ID only for a new version")
+	}
+}
@@ -0,0 +1,384 @@
+// Package immuttest checks map and vector implementations against simple reference models.
+// Operation sequences are run on both the implementation and the model, and every result is
+// compared. The package's own tests fuzz immut.HashMap and immut.Vector with these checkers,
+// and code that wraps them can be fuzzed the same way through the Map and Vector interfaces.
+//
+// Every checker also holds on to an earlier version of the structure while it keeps
+// changing, and checks that it still reads the same at the end.
+package immuttest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+
+	"github.com/eliothedeman/immut"
+)
+
+// A Map is a persistent map. Put and Del return new versions and never change the receiver.
+type Map interface {
+	Put(k, v interface{}) Map
+	Get(k interface{}) (interface{}, bool)
+	Del(k interface{}) (Map, interface{})
+	Len() int
+	Each(f func(k, v interface{}))
+}
+
+// A Vector is a persistent vector indexed from 0. Put returns a new version and never changes
+// the receiver.
+type Vector interface {
+	Put(i int, v interface{}) Vector
+	Get(i int) (interface{}, bool)
+	Size() int
+}
+
+// WrapHashMap adapts an immut.HashMap to Map
+func WrapHashMap(h *immut.HashMap) Map {
+	return hashMap{h}
+}
+
+type hashMap struct {
+	h *immut.HashMap
+}
+
+func (m hashMap) Put(k, v interface{}) Map {
+	return hashMap{m.h.Put(k, v)}
+}
+
+func (m hashMap) Get(k interface{}) (interface{}, bool) {
+	return m.h.Get(k)
+}
+
+func (m hashMap) Del(k interface{}) (Map, interface{}) {
+	h, v := m.h.Del(k)
+	return hashMap{h}, v
+}
+
+func (m hashMap) Len() int {
+	return m.h.Len()
+}
+
+func (m hashMap) Each(f func(k, v interface{})) {
+	m.h.Each(f)
+}
+
+// WrapVector adapts an immut.Vector to Vector
+func WrapVector(v *immut.Vector) Vector {
+	return vector{v}
+}
+
+type vector struct {
+	v *immut.Vector
+}
+
+func (v vector) Put(i int, x interface{}) Vector {
+	return vector{v.v.Put(i, x)}
+}
+
+func (v vector) Get(i int) (interface{}, bool) {
+	return v.v.Get(i)
+}
+
+func (v vector) Size() int {
+	return v.v.Size()
+}
+
+// OpKind is the kind of a single operation in a sequence
+type OpKind int
+
+const (
+	// OpPut puts Val at Key in a map
+	OpPut OpKind = iota
+
+	// OpGet reads Key from a map
+	OpGet
+
+	// OpDel deletes Key from a map
+	OpDel
+
+	// OpAppend puts Val at the end of a vector
+	OpAppend
+
+	// OpIndex reads index Index from a vector
+	OpIndex
+
+	// OpSet replaces the value at index Index of a vector with Val, it is skipped if Index is
+	// out of range
+	OpSet
+)
+
+// String returns the name of the operation
+func (k OpKind) String() string {
+	switch k {
+	case OpPut:
+		return "put"
+	case OpGet:
+		return "get"
+	case OpDel:
+		return "del"
+	case OpAppend:
+		return "append"
+	case OpIndex:
+		return "index"
+	case OpSet:
+		return "set"
+	}
+
+	return "unknown"
+}
+
+// An Op is a single operation. Maps use Key, vectors use Index.
+type Op struct {
+	Kind  OpKind
+	Key   interface{}
+	Index int
+	Val   interface{}
+}
+
+// String returns a readable description of the operation for error messages
+func (o Op) String() string {
+	switch o.Kind {
+	case OpPut:
+		return fmt.Sprintf("put(%#v, %#v)", o.Key, o.Val)
+	case OpGet, OpDel:
+		return fmt.Sprintf("%s(%#v)", o.Kind, o.Key)
+	case OpAppend:
+		return fmt.Sprintf("append(%#v)", o.Val)
+	case OpSet:
+		return fmt.Sprintf("set(%d, %#v)", o.Index, o.Val)
+	}
+
+	return fmt.Sprintf("%s(%d)", o.Kind, o.Index)
+}
+
+// CheckMap runs ops on m and on a Go map, and returns an error describing the first operation
+// whose result differs between the two. m must start out empty. Keys must be comparable.
+func CheckMap(m Map, ops []Op) error {
+	model := map[interface{}]interface{}{}
+
+	var old Map
+	var oldModel map[interface{}]interface{}
+	for i, op := range ops {
+		switch op.Kind {
+		case OpPut:
+			m = m.Put(op.Key, op.Val)
+			model[op.Key] = op.Val
+		case OpGet:
+			v, found := m.Get(op.Key)
+			want, wantFound := model[op.Key]
+			if found != wantFound || !reflect.DeepEqual(v, want) {
+				return fmt.Errorf("op %d %s: got %#v, %t want %#v, %t", i, op, v, found, want, wantFound)
+			}
+		case OpDel:
+			var v interface{}
+			m, v = m.Del(op.Key)
+			if want := model[op.Key]; !reflect.DeepEqual(v, want) {
+				return fmt.Errorf("op %d %s: deleted %#v want %#v", i, op, v, want)
+			}
+			delete(model, op.Key)
+		default:
+			return fmt.Errorf("op %d %s: not a map operation", i, op)
+		}
+
+		if m.Len() != len(model) {
+			return fmt.Errorf("op %d %s: len %d want %d", i, op, m.Len(), len(model))
+		}
+
+		// keep the version halfway through around to check it doesn't change
+		if i == len(ops)/2 {
+			old = m
+			oldModel = copyMap(model)
+		}
+	}
+
+	if err := compareMap(m, model); err != nil {
+		return err
+	}
+
+	if old != nil {
+		if err := compareMap(old, oldModel); err != nil {
+			return fmt.Errorf("earlier version changed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// compareMap returns an error if m doesn't hold exactly the pairs in model
+func compareMap(m Map, model map[interface{}]interface{}) error {
+	if m.Len() != len(model) {
+		return fmt.Errorf("len %d want %d", m.Len(), len(model))
+	}
+
+	seen := 0
+	var err error
+	m.Each(func(k, v interface{}) {
+		seen++
+		if want, found := model[k]; err == nil && (!found || !reflect.DeepEqual(v, want)) {
+			err = fmt.Errorf("key %#v: got %#v want %#v, %t", k, v, want, found)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if seen != len(model) {
+		return fmt.Errorf("each visited %d pairs want %d", seen, len(model))
+	}
+
+	for k, want := range model {
+		if v, found := m.Get(k); !found || !reflect.DeepEqual(v, want) {
+			return fmt.Errorf("key %#v: got %#v, %t want %#v", k, v, found, want)
+		}
+	}
+
+	return nil
+}
+
+func copyMap(m map[interface{}]interface{}) map[interface{}]interface{} {
+	c := make(map[interface{}]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+
+	return c
+}
+
+// CheckVector runs ops on v and on a Go slice, and returns an error describing the first
+// operation whose result differs between the two. v must start out empty.
+func CheckVector(v Vector, ops []Op) error {
+	var model []interface{}
+
+	var old Vector
+	var oldModel []interface{}
+	for i, op := range ops {
+		switch op.Kind {
+		case OpAppend:
+			v = v.Put(v.Size(), op.Val)
+			model = append(model, op.Val)
+		case OpIndex:
+			x, found := v.Get(op.Index)
+			wantFound := op.Index >= 0 && op.Index < len(model)
+			var want interface{}
+			if wantFound {
+				want = model[op.Index]
+			}
+
+			if found != wantFound || !reflect.DeepEqual(x, want) {
+				return fmt.Errorf("op %d %s: got %#v, %t want %#v, %t", i, op, x, found, want, wantFound)
+			}
+		case OpSet:
+			if op.Index >= 0 && op.Index < len(model) {
+				v = v.Put(op.Index, op.Val)
+				model[op.Index] = op.Val
+			}
+		default:
+			return fmt.Errorf("op %d %s: not a vector operation", i, op)
+		}
+
+		if v.Size() != len(model) {
+			return fmt.Errorf("op %d %s: size %d want %d", i, op, v.Size(), len(model))
+		}
+
+		if i == len(ops)/2 {
+			old = v
+			oldModel = append([]interface{}(nil), model...)
+		}
+	}
+
+	if err := compareVector(v, model); err != nil {
+		return err
+	}
+
+	if old != nil {
+		if err := compareVector(old, oldModel); err != nil {
+			return fmt.Errorf("earlier version changed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// compareVector returns an error if v doesn't hold exactly the values in model
+func compareVector(v Vector, model []interface{}) error {
+	if v.Size() != len(model) {
+		return fmt.Errorf("size %d want %d", v.Size(), len(model))
+	}
+
+	for i, want := range model {
+		if x, found := v.Get(i); !found || !reflect.DeepEqual(x, want) {
+			return fmt.Errorf("index %d: got %#v, %t want %#v", i, x, found, want)
+		}
+	}
+
+	return nil
+}
+
+// RandomMapOps returns n random map operations on keys drawn from [0, keys), so that puts,
+// gets and deletes keep hitting the same keys
+func RandomMapOps(r *rand.Rand, n, keys int) []Op {
+	ops := make([]Op, n)
+	for i := range ops {
+		ops[i] = Op{
+			Kind: OpKind(r.Intn(3)),
+			Key:  r.Intn(keys),
+			Val:  r.Int(),
+		}
+	}
+
+	return ops
+}
+
+// RandomVectorOps returns n random vector operations, reading indexes up to a little past the
+// end of the vector
+func RandomVectorOps(r *rand.Rand, n int) []Op {
+	ops := make([]Op, n)
+	size := 0
+	for i := range ops {
+		switch r.Intn(3) {
+		case 0:
+			ops[i] = Op{Kind: OpAppend, Val: r.Int()}
+			size++
+		case 1:
+			ops[i] = Op{Kind: OpIndex, Index: r.Intn(size+2) - 1}
+		default:
+			ops[i] = Op{Kind: OpSet, Index: r.Intn(size + 1), Val: r.Int()}
+		}
+	}
+
+	return ops
+}
+
+// MapOpsFromBytes decodes a fuzzer's input into map operations, two bytes per operation: the
+// kind, and the key, which is also used as the value. Use it from a fuzz target to let the
+// fuzzer explore operation sequences.
+func MapOpsFromBytes(data []byte) []Op {
+	ops := make([]Op, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		ops = append(ops, Op{
+			Kind: OpKind(data[i] % 3),
+			Key:  int(data[i+1]),
+			Val:  int(data[i+1]) + i,
+		})
+	}
+
+	return ops
+}
+
+// VectorOpsFromBytes decodes a fuzzer's input into vector operations, two bytes per
+// operation like MapOpsFromBytes
+func VectorOpsFromBytes(data []byte) []Op {
+	ops := make([]Op, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		switch data[i] % 3 {
+		case 0:
+			ops = append(ops, Op{Kind: OpAppend, Val: int(data[i+1])})
+		case 1:
+			ops = append(ops, Op{Kind: OpIndex, Index: int(data[i+1])})
+		default:
+			ops = append(ops, Op{Kind: OpSet, Index: int(data[i+1]), Val: i})
+		}
+	}
+
+	return ops
+}
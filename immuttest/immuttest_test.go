@@ -0,0 +1,79 @@
+package immuttest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/eliothedeman/immut"
+)
+
+func TestHashMap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		if err := CheckMap(WrapHashMap(immut.NewHashMap()), RandomMapOps(r, 500, 64)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestVector(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		if err := CheckVector(WrapVector(immut.NewVector()), RandomVectorOps(r, 500)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// leaky forgets every delete, the checker should catch it
+type leaky struct {
+	Map
+}
+
+func (l leaky) Put(k, v interface{}) Map {
+	return leaky{l.Map.Put(k, v)}
+}
+
+func (l leaky) Del(k interface{}) (Map, interface{}) {
+	v, _ := l.Get(k)
+	return l, v
+}
+
+func TestCheckMapCatchesBugs(t *testing.T) {
+	ops := []Op{
+		{Kind: OpPut, Key: 1, Val: 1},
+		{Kind: OpDel, Key: 1},
+	}
+
+	if err := CheckMap(leaky{WrapHashMap(immut.NewHashMap())}, ops); err == nil {
+		t.Error("Expected the lost delete to be reported")
+	}
+}
+
+func TestOpsFromBytes(t *testing.T) {
+	if ops := MapOpsFromBytes([]byte{0, 1, 2, 1, 9}); len(ops) != 2 || ops[1].Kind != OpDel {
+		t.Errorf("Unexpected ops %v", ops)
+	}
+
+	if ops := VectorOpsFromBytes([]byte{0, 1, 1, 0, 2, 0}); len(ops) != 3 || ops[1].Kind != OpIndex || ops[2].Kind != OpSet {
+		t.Errorf("Unexpected ops %v", ops)
+	}
+}
+
+func FuzzHashMap(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 2, 2, 1, 1, 1})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := CheckMap(WrapHashMap(immut.NewHashMap()), MapOpsFromBytes(data)); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzVector(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 2, 1, 1, 1, 5})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := CheckVector(WrapVector(immut.NewVector()), VectorOpsFromBytes(data)); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
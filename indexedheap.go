@@ -0,0 +1,205 @@
+package immut
+
+// An IndexedHeap is a persistent priority queue that can also be looked up by key, like a
+// scheduler's table of tasks keyed by ID and ordered by deadline. Every key holds one value and
+// one priority, lower priorities come out first and equal priorities come out in the order they
+// were set. Keys are told apart with ==.
+//
+// The keys live in a keyedMap and the order in a persistent leftist heap. Changing or deleting a
+// key leaves its old heap node behind, those are skipped when they reach the top and the heap
+// is rebuilt once they outnumber the live ones.
+type IndexedHeap[K comparable, V any] struct {
+	items *keyedMap[K, heapItem[V]]
+	heap  *heapNode[K]
+
+	// nodes is the number of nodes in heap, live or not
+	nodes int
+
+	// seq orders equal priorities and tells a key's current heap node from stale ones
+	seq uint64
+}
+
+// heapItem is what the map holds for every key
+type heapItem[V any] struct {
+	val      V
+	priority int64
+	seq      uint64
+}
+
+// heapNode is a node of a persistent leftist heap
+type heapNode[K comparable] struct {
+	key         K
+	priority    int64
+	seq         uint64
+	rank        int
+	left, right *heapNode[K]
+}
+
+// NewIndexedHeap returns an empty heap
+func NewIndexedHeap[K comparable, V any]() *IndexedHeap[K, V] {
+	return &IndexedHeap[K, V]{
+		items: newKeyedMap[K, heapItem[V]](),
+	}
+}
+
+// Len returns the number of keys in the heap
+func (h *IndexedHeap[K, V]) Len() int {
+	return h.items.Len()
+}
+
+// Get returns the value and priority stored at the given key
+func (h *IndexedHeap[K, V]) Get(k K) (V, int64, bool) {
+	var zero V
+	i, found := h.items.Get(k)
+	if !found {
+		return zero, 0, false
+	}
+
+	return i.val, i.priority, true
+}
+
+// Set returns a new heap with v stored at k with the given priority, replacing the value and
+// priority k had before
+func (h *IndexedHeap[K, V]) Set(k K, v V, priority int64) *IndexedHeap[K, V] {
+	n := &IndexedHeap[K, V]{
+		items: h.items.Put(k, heapItem[V]{val: v, priority: priority, seq: h.seq}),
+		heap:  h.heap.merge(&heapNode[K]{key: k, priority: priority, seq: h.seq, rank: 1}),
+		nodes: h.nodes + 1,
+		seq:   h.seq + 1,
+	}
+
+	return n.clean()
+}
+
+// UpdatePriority returns a new heap with k moved to the given priority. It returns false and
+// the heap unchanged if k isn't in it.
+func (h *IndexedHeap[K, V]) UpdatePriority(k K, priority int64) (*IndexedHeap[K, V], bool) {
+	v, _, found := h.Get(k)
+	if !found {
+		return h, false
+	}
+
+	return h.Set(k, v, priority), true
+}
+
+// Del returns a new heap without k
+func (h *IndexedHeap[K, V]) Del(k K) *IndexedHeap[K, V] {
+	items := h.items.Del(k)
+	if items == h.items {
+		return h
+	}
+
+	n := &IndexedHeap[K, V]{
+		items: items,
+		heap:  h.heap,
+		nodes: h.nodes,
+		seq:   h.seq,
+	}
+
+	return n.clean()
+}
+
+// PeekMin returns the key with the lowest priority along with its value and priority
+func (h *IndexedHeap[K, V]) PeekMin() (K, V, int64, bool) {
+	var k K
+	var v V
+	if h.heap == nil {
+		return k, v, 0, false
+	}
+
+	v, p, _ := h.Get(h.heap.key)
+	return h.heap.key, v, p, true
+}
+
+// PopMin returns the key with the lowest priority, its value, and a new heap without it. It
+// returns false and the heap unchanged if the heap is empty.
+func (h *IndexedHeap[K, V]) PopMin() (K, V, *IndexedHeap[K, V], bool) {
+	k, v, _, found := h.PeekMin()
+	if !found {
+		return k, v, h, false
+	}
+
+	items := h.items.Del(k)
+	n := &IndexedHeap[K, V]{
+		items: items,
+		heap:  h.heap.left.merge(h.heap.right),
+		nodes: h.nodes - 1,
+		seq:   h.seq,
+	}
+
+	return k, v, n.clean(), true
+}
+
+// Each runs f on every key, value and priority in the heap in no particular order
+func (h *IndexedHeap[K, V]) Each(f func(k K, v V, priority int64)) {
+	h.items.Each(func(k K, i heapItem[V]) {
+		f(k, i.val, i.priority)
+	})
+}
+
+// live returns true if n is the current heap node for its key
+func (h *IndexedHeap[K, V]) live(n *heapNode[K]) bool {
+	i, found := h.items.Get(n.key)
+	return found && i.seq == n.seq
+}
+
+// clean pops stale nodes off the top of the heap, so the top is always a live key, and rebuilds
+// the heap once most of it is stale. It may only be called on a heap nobody else has seen yet.
+func (h *IndexedHeap[K, V]) clean() *IndexedHeap[K, V] {
+	if h.nodes > 2*h.Len()+8 {
+		h.heap = nil
+		h.nodes = 0
+		h.items.Each(func(k K, i heapItem[V]) {
+			h.heap = h.heap.merge(&heapNode[K]{key: k, priority: i.priority, seq: i.seq, rank: 1})
+			h.nodes++
+		})
+	}
+
+	for h.heap != nil && !h.live(h.heap) {
+		h.heap = h.heap.left.merge(h.heap.right)
+		h.nodes--
+	}
+
+	return h
+}
+
+// less orders nodes by priority, then by when they were set
+func (t *heapNode[K]) less(o *heapNode[K]) bool {
+	if t.priority != o.priority {
+		return t.priority < o.priority
+	}
+
+	return t.seq < o.seq
+}
+
+// merge returns a heap holding the nodes of both heaps, copying only the nodes along their
+// right spines
+func (t *heapNode[K]) merge(o *heapNode[K]) *heapNode[K] {
+	if t == nil {
+		return o
+	}
+	if o == nil {
+		return t
+	}
+	if o.less(t) {
+		t, o = o, t
+	}
+
+	c := *t
+	c.right = t.right.merge(o)
+	if c.left.getRank() < c.right.getRank() {
+		c.left, c.right = c.right, c.left
+	}
+	c.rank = c.right.getRank() + 1
+
+	return &c
+}
+
+// getRank returns the length of the node's right spine, 0 for nil
+func (t *heapNode[K]) getRank() int {
+	if t == nil {
+		return 0
+	}
+
+	return t.rank
+}
@@ -0,0 +1,139 @@
+package immut
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestIndexedHeap(t *testing.T) {
+	h := NewIndexedHeap[string, int]()
+	h = h.Set("a", 1, 30)
+	h = h.Set("b", 2, 10)
+	h = h.Set("c", 3, 20)
+	before := h
+
+	if v, p, found := h.Get("c"); !found || v != 3 || p != 20 {
+		t.Errorf("Expected 3 at 20 got %v at %d", v, p)
+	}
+
+	h, ok := h.UpdatePriority("a", 5)
+	if !ok {
+		t.Fatal("Expected a to be updated")
+	}
+
+	if _, ok := h.UpdatePriority("z", 5); ok {
+		t.Error("Expected a missing key not to be updated")
+	}
+
+	var order []string
+	for h.Len() > 0 {
+		k, _, n, found := h.PopMin()
+		if !found {
+			t.Fatal("Expected a key")
+		}
+		order = append(order, k)
+		h = n
+	}
+
+	if len(order) != 3 || order[0] != "a" || order[1] != "b" || order[2] != "c" {
+		t.Errorf("Expected [a b c] got %v", order)
+	}
+
+	if _, _, _, found := h.PopMin(); found {
+		t.Error("Expected nothing to pop from an empty heap")
+	}
+
+	if k, _, p, _ := before.PeekMin(); before.Len() != 3 || k != "b" || p != 10 {
+		t.Errorf("Expected older versions to be untouched, got %s at %d", k, p)
+	}
+}
+
+func TestIndexedHeapTies(t *testing.T) {
+	h := NewIndexedHeap[int, int]()
+	for i := 0; i < 5; i++ {
+		h = h.Set(i, i, 1)
+	}
+
+	for i := 0; i < 5; i++ {
+		k, _, n, _ := h.PopMin()
+		if k != i {
+			t.Fatalf("Expected equal priorities in the order they were set, got %d want %d", k, i)
+		}
+		h = n
+	}
+}
+
+func TestIndexedHeapRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := NewIndexedHeap[int, int]()
+	model := map[int]int64{}
+
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(50)
+		switch r.Intn(4) {
+		case 0, 1:
+			p := int64(r.Intn(100))
+			h = h.Set(k, k*10, p)
+			model[k] = p
+		case 2:
+			h = h.Del(k)
+			delete(model, k)
+		case 3:
+			k, v, n, found := h.PopMin()
+			if found != (len(model) > 0) {
+				t.Fatalf("Expected found to be %t", len(model) > 0)
+			}
+			if !found {
+				continue
+			}
+
+			for _, p := range model {
+				if p < model[k] {
+					t.Fatalf("Expected %d at %d to be the minimum, found %d", k, model[k], p)
+				}
+			}
+			if v != k*10 {
+				t.Fatalf("Expected %d got %d", k*10, v)
+			}
+			delete(model, k)
+			h = n
+		}
+
+		if h.Len() != len(model) {
+			t.Fatalf("Expected %d keys got %d", len(model), h.Len())
+		}
+		if h.nodes > 2*h.Len()+8 {
+			t.Fatalf("Expected stale nodes to be dropped, have %d nodes for %d keys", h.nodes, h.Len())
+		}
+	}
+
+	var keys []int
+	h.Each(func(k, v int, p int64) {
+		if model[k] != p {
+			t.Errorf("Expected %d at %d got %d", k, model[k], p)
+		}
+		keys = append(keys, k)
+	})
+	if len(keys) != len(model) {
+		t.Errorf("Expected %d keys got %v", len(model), keys)
+	}
+}
+
+func TestIndexedHeapCollidingKeys(t *testing.T) {
+	a := printsSame{A: "a b", B: ""}
+	b := printsSame{A: "a", B: "b "}
+
+	h := NewIndexedHeap[printsSame, int]().Set(a, 1, 20).Set(b, 2, 10)
+	if h.Len() != 2 {
+		t.Fatalf("Expected 2 tasks got %d", h.Len())
+	}
+
+	k, v, h, _ := h.PopMin()
+	if k != b || v != 2 {
+		t.Errorf("Expected b first got %v %d", k, v)
+	}
+
+	if k, v, _, _ := h.PopMin(); k != a || v != 1 {
+		t.Errorf("Expected a next got %v %d", k, v)
+	}
+}
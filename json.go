@@ -0,0 +1,231 @@
+package immut
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// MarshalJSON encodes the map as a JSON object. Keys that implement encoding.TextMarshaler are
+// encoded with it, strings are used as is and integer kinds are formatted with strconv. Any
+// other key type is an error.
+func (h *HashMap) MarshalJSON() ([]byte, error) {
+	return h.marshalJSON(false)
+}
+
+// MarshalJSONOrdered encodes the map like MarshalJSON but with the object keys sorted by their
+// encoded form, so equal maps always encode to the same bytes no matter how they were built.
+// HashMaps nested in the map or in its Vectors are sorted too. Use it when snapshots are hashed
+// or compared byte for byte.
+func (h *HashMap) MarshalJSONOrdered() ([]byte, error) {
+	return h.marshalJSON(true)
+}
+
+// jsonField is a single encoded object key and the value that goes with it
+type jsonField struct {
+	key string
+	val interface{}
+}
+
+// marshalJSON encodes the map as a JSON object, sorting the object keys if sorted is set
+func (h *HashMap) marshalJSON(sorted bool) ([]byte, error) {
+	fields := make([]jsonField, 0, h.Len())
+	seen := make(map[string]interface{}, h.Len())
+	for _, e := range h.Entries() {
+		k, err := jsonKey(e.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if other, dup := seen[k]; dup {
+			return nil, fmt.Errorf("immut: keys %#v and %#v both encode to %q", other, e.Key, k)
+		}
+		seen[k] = e.Key
+		fields = append(fields, jsonField{key: k, val: e.Val})
+	}
+
+	if sorted {
+		sort.Slice(fields, func(i, j int) bool {
+			return fields[i].key < fields[j].key
+		})
+	}
+
+	b := bytes.NewBuffer(nil)
+	b.WriteString("{")
+	for i, f := range fields {
+		kb, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+
+		vb, err := marshalJSONValue(f.val, sorted)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.Write(kb)
+		b.WriteString(":")
+		b.Write(vb)
+	}
+	b.WriteString("}")
+
+	return b.Bytes(), nil
+}
+
+// marshalJSONValue encodes a value held by a HashMap or Vector, keeping nested HashMaps sorted
+// if sorted is set
+func marshalJSONValue(x interface{}, sorted bool) ([]byte, error) {
+	if sorted {
+		switch x := x.(type) {
+		case *HashMap:
+			if x != nil {
+				return x.marshalJSON(true)
+			}
+		case *Vector:
+			if x != nil {
+				return x.marshalJSON(true)
+			}
+		}
+	}
+
+	return json.Marshal(x)
+}
+
+// UnmarshalJSON decodes a JSON object into the map. JSON carries no key types, so every key is
+// stored as a string, use DecodeJSON to convert them.
+func (h *HashMap) UnmarshalJSON(data []byte) error {
+	n, err := DecodeJSON(data, func(k string) (interface{}, error) {
+		return k, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*h = *n
+	return nil
+}
+
+// DecodeJSON decodes a JSON object into a new HashMap, turning every object key into a map key
+// with the given function. Values are decoded the same way json.Unmarshal decodes into an
+// interface{}.
+func DecodeJSON(data []byte, key func(string) (interface{}, error)) (*HashMap, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	h := NewHashMap()
+	for k, rv := range raw {
+		var v interface{}
+		if err := json.Unmarshal(rv, &v); err != nil {
+			return nil, err
+		}
+
+		mk, err := key(k)
+		if err != nil {
+			return nil, err
+		}
+		h = h.Put(mk, v)
+	}
+
+	return h, nil
+}
+
+// jsonKey returns the JSON object key for a map key
+func jsonKey(k interface{}) (string, error) {
+	switch k := k.(type) {
+	case encoding.TextMarshaler:
+		b, err := k.MarshalText()
+		return string(b), err
+	case string:
+		return k, nil
+	}
+
+	v := reflect.ValueOf(k)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("immut: unsupported JSON key type %T, keys must be strings, integers or implement encoding.TextMarshaler", k)
+}
+
+// MarshalJSON encodes the vector as a JSON array. Elements are encoded one at a time straight
+// into the output, without copying the vector into a slice first. A missing index is an error
+// rather than being skipped, so the array always has Size elements.
+func (v *Vector) MarshalJSON() ([]byte, error) {
+	return v.marshalJSON(false)
+}
+
+// marshalJSON encodes the vector as a JSON array, sorting the keys of nested HashMaps if sorted
+// is set
+func (v *Vector) marshalJSON(sorted bool) ([]byte, error) {
+	b := bytes.NewBuffer(nil)
+	b.WriteString("[")
+
+	for i := 0; i < v.Size(); i++ {
+		x, found := v.Get(i)
+		if !found {
+			return nil, fmt.Errorf("immut: vector of %d has no element at index %d", v.Size(), i)
+		}
+
+		vb, err := marshalJSONValue(x, sorted)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.Write(vb)
+	}
+	b.WriteString("]")
+
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a JSON array into the vector. Elements are decoded one at a time as
+// they are read, the same way json.Unmarshal decodes into an interface{}. JSON null
+// leaves the vector unchanged.
+func (v *Vector) UnmarshalJSON(data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	t, err := d.Token()
+	if err != nil {
+		return err
+	}
+
+	if t == nil {
+		return nil
+	}
+
+	if t != json.Delim('[') {
+		return fmt.Errorf("immut: can't decode JSON %v into a vector, expected an array", t)
+	}
+
+	n := NewVector()
+	for i := 0; d.More(); i++ {
+		var x interface{}
+		if err := d.Decode(&x); err != nil {
+			return err
+		}
+		n = n.Put(i, x)
+	}
+
+	if _, err := d.Token(); err != nil {
+		return err
+	}
+
+	*v = *n
+	return nil
+}
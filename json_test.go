@@ -0,0 +1,193 @@
+package immut
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHashMapMarshalJSON(t *testing.T) {
+	h := NewHashMap().Put("a", 1).Put(2, []int{1, 2}).Put(uint8(3), "three")
+
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out map[string]interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out) != 3 || out["a"] != 1.0 || out["3"] != "three" {
+		t.Errorf("Unexpected output %s", b)
+	}
+}
+
+func TestHashMapMarshalJSONTextKey(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	b, err := json.Marshal(NewHashMap().Put(ts, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"2020-01-02T03:04:05Z":true}` {
+		t.Errorf("Unexpected output %s", b)
+	}
+}
+
+func TestHashMapMarshalJSONErrors(t *testing.T) {
+	if _, err := json.Marshal(NewHashMap().Put(1.5, 1)); err == nil {
+		t.Error("Expected an error for a float key")
+	}
+
+	if _, err := json.Marshal(NewHashMap().Put(1, 1).Put("1", 1)); err == nil {
+		t.Error("Expected an error for two keys with the same encoding")
+	}
+}
+
+func TestHashMapUnmarshalJSON(t *testing.T) {
+	h := NewHashMap()
+	if err := json.Unmarshal([]byte(`{"a": 1, "b": [1, 2]}`), h); err != nil {
+		t.Fatal(err)
+	}
+
+	if h.Len() != 2 {
+		t.Errorf("Expected 2 got %d", h.Len())
+	}
+
+	if v, _ := h.Get("a"); v != 1.0 {
+		t.Errorf("Expected 1 got %v", v)
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	h, err := DecodeJSON([]byte(`{"1": "one", "2": "two"}`), func(k string) (interface{}, error) {
+		return strconv.Atoi(k)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, _ := h.Get(2); v != "two" {
+		t.Errorf("Expected two got %v", v)
+	}
+
+	_, err = DecodeJSON([]byte(`{"x": 1}`), func(k string) (interface{}, error) {
+		return strconv.Atoi(k)
+	})
+	if err == nil {
+		t.Error("Expected the key error to be returned")
+	}
+}
+
+func TestVectorJSON(t *testing.T) {
+	v := NewVector().Put(0, "a").Put(1, 2).Put(2, []int{3})
+
+	b, err := json.Marshal(struct {
+		V *Vector `json:"v"`
+	}{v})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"v":["a",2,[3]]}` {
+		t.Errorf("Unexpected JSON %s", b)
+	}
+
+	var out struct {
+		V *Vector `json:"v"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.V.Size() != 3 {
+		t.Fatalf("Expected 3 got %d", out.V.Size())
+	}
+
+	if x, _ := out.V.Get(1); x != 2.0 {
+		t.Errorf("Expected 2 got %v", x)
+	}
+
+	if b, _ := json.Marshal(NewVector()); string(b) != "[]" {
+		t.Errorf("Expected [] got %s", b)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a": 1}`), NewVector()); err == nil {
+		t.Error("Expected an error decoding an object into a vector")
+	}
+}
+
+func TestHashMapMarshalJSONOrdered(t *testing.T) {
+	h := NewHashMap().Put("b", 2).Put("c", 3).Put("a", 1).Put(10, "ten")
+
+	b, err := h.MarshalJSONOrdered()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"10":"ten","a":1,"b":2,"c":3}` {
+		t.Errorf("Unexpected JSON %s", b)
+	}
+
+	// build the same nested maps in opposite orders, big enough that their tries differ
+	forward, reverse := NewHashMap(), NewHashMap()
+	innerF, innerR := NewHashMap(), NewHashMap()
+	want := map[string]interface{}{}
+	inner := map[string]interface{}{}
+	for i := 0; i < 200; i++ {
+		k := fmt.Sprint(i)
+		forward = forward.Put(k, i)
+		innerF = innerF.Put(k, i)
+		reverse = reverse.Put(fmt.Sprint(199-i), 199-i)
+		innerR = innerR.Put(fmt.Sprint(199-i), 199-i)
+		want[k] = i
+		inner[k] = i
+	}
+	forward = forward.Put("inner", innerF).Put("list", NewVector().Put(0, innerF))
+	reverse = reverse.Put("list", NewVector().Put(0, innerR)).Put("inner", innerR)
+	want["inner"] = inner
+	want["list"] = []interface{}{inner}
+
+	fb, err := forward.MarshalJSONOrdered()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rb, err := reverse.MarshalJSONOrdered()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// encoding/json sorts the keys of Go maps
+	wb, _ := json.Marshal(want)
+	if string(fb) != string(wb) || string(rb) != string(wb) {
+		t.Errorf("Expected equal nested maps to encode the same sorted bytes, got %s and %s", fb, rb)
+	}
+
+	if _, err := NewHashMap().Put(1.5, 1).MarshalJSONOrdered(); err == nil {
+		t.Error("Expected an error for an unsupported key type")
+	}
+}
+
+func TestVectorJSONNull(t *testing.T) {
+	v := NewVector().Put(0, "a")
+	if err := json.Unmarshal([]byte("null"), v); err != nil {
+		t.Fatal(err)
+	}
+
+	if x, _ := v.Get(0); x != "a" || v.Size() != 1 {
+		t.Errorf("Expected null to leave the vector alone, got %v and %d", x, v.Size())
+	}
+}
+
+func TestVectorJSONHole(t *testing.T) {
+	v := NewVector().Put(0, "a").Put(5, "f")
+
+	if _, err := v.MarshalJSON(); err == nil {
+		t.Error("Expected a vector with a hole to fail to encode")
+	}
+}
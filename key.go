@@ -0,0 +1,77 @@
+package immut
+
+import "encoding/binary"
+
+// Key2 is a comparable composite key made of two parts, for maps keyed by pairs like
+// (tenantID, userID)
+type Key2[A, B comparable] struct {
+	First  A
+	Second B
+}
+
+// NewKey2 creates and returns a Key2
+func NewKey2[A, B comparable](a A, b B) Key2[A, B] {
+	return Key2[A, B]{
+		First:  a,
+		Second: b,
+	}
+}
+
+// Bytes returns the parts of the key framed by their lengths, so ("a b", "c") and ("a", "b c")
+// don't collide
+func (k Key2[A, B]) Bytes() []byte {
+	return frameParts(k.First, k.Second)
+}
+
+// Key3 is a comparable composite key made of three parts
+type Key3[A, B, C comparable] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// NewKey3 creates and returns a Key3
+func NewKey3[A, B, C comparable](a A, b B, c C) Key3[A, B, C] {
+	return Key3[A, B, C]{
+		First:  a,
+		Second: b,
+		Third:  c,
+	}
+}
+
+// Bytes returns the parts of the key framed by their lengths
+func (k Key3[A, B, C]) Bytes() []byte {
+	return frameParts(k.First, k.Second, k.Third)
+}
+
+// GetBy2 returns the value stored in the map at the Key2 made of a and b
+func GetBy2[A, B comparable](h *HashMap, a A, b B) (interface{}, bool) {
+	return h.Get(NewKey2(a, b))
+}
+
+// PutBy2 stores v in the map at the Key2 made of a and b
+func PutBy2[A, B comparable](h *HashMap, a A, b B, v interface{}) *HashMap {
+	return h.Put(NewKey2(a, b), v)
+}
+
+// GetBy3 returns the value stored in the map at the Key3 made of a, b and c
+func GetBy3[A, B, C comparable](h *HashMap, a A, b B, c C) (interface{}, bool) {
+	return h.Get(NewKey3(a, b, c))
+}
+
+// PutBy3 stores v in the map at the Key3 made of a, b and c
+func PutBy3[A, B, C comparable](h *HashMap, a A, b B, c C, v interface{}) *HashMap {
+	return h.Put(NewKey3(a, b, c), v)
+}
+
+// frameParts concatenates the byte representation of every part, each prefixed by its length
+func frameParts(parts ...interface{}) []byte {
+	var b []byte
+	for _, p := range parts {
+		x := iToBytes(p)
+		b = binary.AppendUvarint(b, uint64(len(x)))
+		b = append(b, x...)
+	}
+
+	return b
+}
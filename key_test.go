@@ -0,0 +1,53 @@
+package immut
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestKey2(t *testing.T) {
+	h := PutBy2(NewHashMap(), "tenant", 7, "a")
+	h = h.Put(NewKey2("tenant", 8), "b")
+
+	if v, _ := GetBy2(h, "tenant", 7); v != "a" {
+		t.Errorf("Expected a got %v", v)
+	}
+
+	if v, _ := h.Get(NewKey2("tenant", 8)); v != "b" {
+		t.Errorf("Expected b got %v", v)
+	}
+
+	if _, found := GetBy2(h, "tenant", "7"); found {
+		t.Error("Keys with parts of a different type should not match")
+	}
+
+	if NewKey2(1, 2) != (Key2[int, int]{First: 1, Second: 2}) {
+		t.Error("Key2 should be comparable by value")
+	}
+}
+
+func TestKey2NoCollision(t *testing.T) {
+	a := NewKey2("a b", "c")
+	b := NewKey2("a", "b c")
+
+	if bytes.Equal(iToBytes(a), iToBytes(b)) {
+		t.Error("Parts should be framed so they can't run into each other")
+	}
+
+	h := NewHashMap().Put(a, 1).Put(b, 2)
+	if h.Len() != 2 {
+		t.Errorf("Expected 2 got %d", h.Len())
+	}
+}
+
+func TestKey3(t *testing.T) {
+	h := PutBy3(NewHashMap(), 1, "x", 2.5, true)
+
+	if v, _ := GetBy3(h, 1, "x", 2.5); v != true {
+		t.Errorf("Expected true got %v", v)
+	}
+
+	if _, found := GetBy3(h, 1, "x", 2.6); found {
+		t.Error("Expected no match for a different third part")
+	}
+}
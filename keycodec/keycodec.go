@@ -0,0 +1,242 @@
+// Package keycodec encodes keys into byte strings whose lexicographic order matches the natural
+// order of the values they came from, so they can back range scans over byte keyed structures
+// like immut.Trie.
+//
+// Integers are written big-endian with the sign bit flipped, floats have their bits flipped so
+// negative values sort first, and strings are escaped and terminated rather than length
+// prefixed so that "ab" still sorts before "b" when it is part of a tuple.
+package keycodec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	UnsupportedType = errors.New("unsupported key type")
+	Malformed       = errors.New("malformed key")
+)
+
+// Type tags written in front of every value in a tuple. Their order decides how values of
+// different types compare to each other.
+const (
+	tagNil = iota
+	tagFalse
+	tagTrue
+	tagInt
+	tagUint
+	tagFloat
+	tagString
+)
+
+const (
+	escape     = 0x00
+	escaped00  = 0xff
+	terminator = 0x01
+)
+
+// AppendUint64 appends the order preserving encoding of v to dst
+func AppendUint64(dst []byte, v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return append(dst, b[:]...)
+}
+
+// AppendInt64 appends the order preserving encoding of v to dst
+func AppendInt64(dst []byte, v int64) []byte {
+	return AppendUint64(dst, uint64(v)^(1<<63))
+}
+
+// AppendFloat64 appends the order preserving encoding of v to dst. Every NaN, whatever its
+// sign and payload, is encoded as the same quiet NaN and sorts after +Inf.
+func AppendFloat64(dst []byte, v float64) []byte {
+	if math.IsNaN(v) {
+		v = math.NaN()
+	}
+
+	b := math.Float64bits(v)
+	if b&(1<<63) != 0 {
+		b = ^b
+	} else {
+		b |= 1 << 63
+	}
+
+	return AppendUint64(dst, b)
+}
+
+// AppendBytes appends the order preserving encoding of v to dst. Every 0x00 byte is escaped to
+// 0x00 0xff and the value is terminated by 0x00 0x01.
+func AppendBytes(dst []byte, v []byte) []byte {
+	for _, c := range v {
+		if c == escape {
+			dst = append(dst, escape, escaped00)
+		} else {
+			dst = append(dst, c)
+		}
+	}
+
+	return append(dst, escape, terminator)
+}
+
+// AppendString appends the order preserving encoding of v to dst
+func AppendString(dst []byte, v string) []byte {
+	return AppendBytes(dst, []byte(v))
+}
+
+// DecodeUint64 decodes a value written by AppendUint64 and returns the rest of b
+func DecodeUint64(b []byte) (uint64, []byte, error) {
+	if len(b) < 8 {
+		return 0, b, Malformed
+	}
+
+	return binary.BigEndian.Uint64(b), b[8:], nil
+}
+
+// DecodeInt64 decodes a value written by AppendInt64 and returns the rest of b
+func DecodeInt64(b []byte) (int64, []byte, error) {
+	u, rest, err := DecodeUint64(b)
+	return int64(u ^ (1 << 63)), rest, err
+}
+
+// DecodeFloat64 decodes a value written by AppendFloat64 and returns the rest of b
+func DecodeFloat64(b []byte) (float64, []byte, error) {
+	u, rest, err := DecodeUint64(b)
+	if u&(1<<63) != 0 {
+		u &^= 1 << 63
+	} else {
+		u = ^u
+	}
+
+	return math.Float64frombits(u), rest, err
+}
+
+// DecodeBytes decodes a value written by AppendBytes and returns the rest of b
+func DecodeBytes(b []byte) ([]byte, []byte, error) {
+	var v []byte
+	for i := 0; i < len(b); i++ {
+		if b[i] != escape {
+			v = append(v, b[i])
+			continue
+		}
+
+		if i+1 >= len(b) {
+			return nil, b, Malformed
+		}
+
+		switch b[i+1] {
+		case escaped00:
+			v = append(v, escape)
+			i++
+		case terminator:
+			return v, b[i+2:], nil
+		default:
+			return nil, b, Malformed
+		}
+	}
+
+	return nil, b, Malformed
+}
+
+// DecodeString decodes a value written by AppendString and returns the rest of b
+func DecodeString(b []byte) (string, []byte, error) {
+	v, rest, err := DecodeBytes(b)
+	return string(v), rest, err
+}
+
+// Tuple encodes the values one after the other, each prefixed with a type tag, so tuples sort by
+// their first value, then their second and so on. Signed ints, unsigned ints, floats, strings,
+// []byte, bools and nil are supported. Values of different types sort by type, not by value.
+func Tuple(vals ...interface{}) ([]byte, error) {
+	var b []byte
+	for _, v := range vals {
+		var err error
+		b, err = AppendValue(b, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// AppendValue appends the tagged encoding of a single tuple value to dst
+func AppendValue(dst []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(dst, tagNil), nil
+	case bool:
+		if v {
+			return append(dst, tagTrue), nil
+		}
+		return append(dst, tagFalse), nil
+	case int:
+		return AppendInt64(append(dst, tagInt), int64(v)), nil
+	case int8:
+		return AppendInt64(append(dst, tagInt), int64(v)), nil
+	case int16:
+		return AppendInt64(append(dst, tagInt), int64(v)), nil
+	case int32:
+		return AppendInt64(append(dst, tagInt), int64(v)), nil
+	case int64:
+		return AppendInt64(append(dst, tagInt), v), nil
+	case uint:
+		return AppendUint64(append(dst, tagUint), uint64(v)), nil
+	case uint8:
+		return AppendUint64(append(dst, tagUint), uint64(v)), nil
+	case uint16:
+		return AppendUint64(append(dst, tagUint), uint64(v)), nil
+	case uint32:
+		return AppendUint64(append(dst, tagUint), uint64(v)), nil
+	case uint64:
+		return AppendUint64(append(dst, tagUint), v), nil
+	case float32:
+		return AppendFloat64(append(dst, tagFloat), float64(v)), nil
+	case float64:
+		return AppendFloat64(append(dst, tagFloat), v), nil
+	case string:
+		return AppendString(append(dst, tagString), v), nil
+	case []byte:
+		return AppendBytes(append(dst, tagString), v), nil
+	}
+
+	return nil, fmt.Errorf("%w: %T", UnsupportedType, v)
+}
+
+// DecodeTuple decodes a tuple written by Tuple. Ints come back as int64, uints as uint64,
+// floats as float64 and strings and []byte as string.
+func DecodeTuple(b []byte) ([]interface{}, error) {
+	var vals []interface{}
+	for len(b) > 0 {
+		var v interface{}
+		var err error
+		tag := b[0]
+		b = b[1:]
+
+		switch tag {
+		case tagNil:
+		case tagFalse:
+			v = false
+		case tagTrue:
+			v = true
+		case tagInt:
+			v, b, err = DecodeInt64(b)
+		case tagUint:
+			v, b, err = DecodeUint64(b)
+		case tagFloat:
+			v, b, err = DecodeFloat64(b)
+		case tagString:
+			v, b, err = DecodeString(b)
+		default:
+			err = Malformed
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, v)
+	}
+
+	return vals, nil
+}
@@ -0,0 +1,130 @@
+package keycodec
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func expectOrdered(t *testing.T, keys [][]byte) {
+	t.Helper()
+	for i := 1; i < len(keys); i++ {
+		if bytes.Compare(keys[i-1], keys[i]) >= 0 {
+			t.Errorf("Expected % x < % x", keys[i-1], keys[i])
+		}
+	}
+}
+
+func TestInt64Order(t *testing.T) {
+	vals := []int64{math.MinInt64, -1000, -1, 0, 1, 255, 256, math.MaxInt64}
+	var keys [][]byte
+	for _, v := range vals {
+		k := AppendInt64(nil, v)
+		keys = append(keys, k)
+
+		if got, _, err := DecodeInt64(k); err != nil || got != v {
+			t.Errorf("Expected %d got %d, %v", v, got, err)
+		}
+	}
+	expectOrdered(t, keys)
+}
+
+func TestFloat64Order(t *testing.T) {
+	vals := []float64{math.Inf(-1), -1e10, -1.5, -0.25, 0, 0.25, 1.5, 1e10, math.Inf(1)}
+	var keys [][]byte
+	for _, v := range vals {
+		k := AppendFloat64(nil, v)
+		keys = append(keys, k)
+
+		if got, _, err := DecodeFloat64(k); err != nil || got != v {
+			t.Errorf("Expected %v got %v, %v", v, got, err)
+		}
+	}
+	expectOrdered(t, keys)
+}
+
+func TestStringOrder(t *testing.T) {
+	vals := []string{"", "\x00", "\x00\x00", "\x00a", "a", "a\x00", "ab", "b"}
+	var keys [][]byte
+	for _, v := range vals {
+		k := AppendString(nil, v)
+		keys = append(keys, k)
+
+		if got, rest, err := DecodeString(k); err != nil || got != v || len(rest) != 0 {
+			t.Errorf("Expected %q got %q, %v", v, got, err)
+		}
+	}
+	expectOrdered(t, keys)
+}
+
+func TestTupleOrder(t *testing.T) {
+	tuples := [][]interface{}{
+		{"a", 1},
+		{"a", 2},
+		{"ab", -5},
+		{"b", -10},
+		{"b", 3, "x"},
+	}
+
+	var keys [][]byte
+	for _, tup := range tuples {
+		k, err := Tuple(tup...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, k)
+	}
+	expectOrdered(t, keys)
+}
+
+func TestTupleRoundTrip(t *testing.T) {
+	k, err := Tuple("tenant", int32(-7), uint8(200), 2.5, true, nil, []byte("x\x00y"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vals, err := DecodeTuple(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []interface{}{"tenant", int64(-7), uint64(200), 2.5, true, nil, "x\x00y"}
+	if len(vals) != len(want) {
+		t.Fatalf("Expected %v got %v", want, vals)
+	}
+
+	for i := range want {
+		if vals[i] != want[i] {
+			t.Errorf("Expected %#v got %#v", want[i], vals[i])
+		}
+	}
+}
+
+func TestTupleErrors(t *testing.T) {
+	if _, err := Tuple(struct{}{}); err == nil {
+		t.Error("Expected an error for an unsupported type")
+	}
+
+	if _, err := DecodeTuple([]byte{tagString, 'a'}); err != Malformed {
+		t.Errorf("Expected %v got %v", Malformed, err)
+	}
+
+	if _, err := DecodeTuple([]byte{tagInt, 1, 2}); err != Malformed {
+		t.Errorf("Expected %v got %v", Malformed, err)
+	}
+}
+
+func TestFloat64NaN(t *testing.T) {
+	inf := AppendFloat64(nil, math.Inf(1))
+	want := AppendFloat64(nil, math.NaN())
+	for _, bits := range []uint64{0x7ff8000000000000, 0xfff8000000000000, 0x7ff0000000000001, 0xffffffffffffffff} {
+		k := AppendFloat64(nil, math.Float64frombits(bits))
+		if !bytes.Equal(k, want) {
+			t.Errorf("Expected NaN %x to encode as %x got %x", bits, want, k)
+		}
+
+		if bytes.Compare(k, inf) <= 0 {
+			t.Errorf("Expected NaN %x to sort after +Inf", bits)
+		}
+	}
+}
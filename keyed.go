@@ -0,0 +1,103 @@
+package immut
+
+// keyedMap is a persistent map that tells keys apart with K's own ==. A HashMap keys through
+// iToBytes, which falls back to fmt.Sprint for types it doesn't know, so two distinct struct
+// keys can land on the same slot. keyedMap keeps a bucket of every key that landed on a slot
+// and picks the right one out of it.
+type keyedMap[K comparable, V any] struct {
+	m    *HashMap
+	size int
+}
+
+// keyedEntry is a key and its value in a keyedMap bucket
+type keyedEntry[K comparable, V any] struct {
+	k K
+	v V
+}
+
+func newKeyedMap[K comparable, V any]() *keyedMap[K, V] {
+	return &keyedMap[K, V]{
+		m: NewHashMap(),
+	}
+}
+
+// Len returns the number of keys in the map
+func (m *keyedMap[K, V]) Len() int {
+	return m.size
+}
+
+// bucket returns the keys that share k's slot
+func (m *keyedMap[K, V]) bucket(k K) []keyedEntry[K, V] {
+	x, found := m.m.Get(k)
+	if !found {
+		return nil
+	}
+
+	return x.([]keyedEntry[K, V])
+}
+
+// Get returns the value stored at k
+func (m *keyedMap[K, V]) Get(k K) (V, bool) {
+	for _, e := range m.bucket(k) {
+		if e.k == k {
+			return e.v, true
+		}
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Put returns a new map with v stored at k
+func (m *keyedMap[K, V]) Put(k K, v V) *keyedMap[K, V] {
+	b := m.bucket(k)
+	n := make([]keyedEntry[K, V], 0, len(b)+1)
+	size := m.size + 1
+	for _, e := range b {
+		if e.k == k {
+			size--
+			continue
+		}
+		n = append(n, e)
+	}
+
+	return &keyedMap[K, V]{
+		m:    m.m.Put(k, append(n, keyedEntry[K, V]{k: k, v: v})),
+		size: size,
+	}
+}
+
+// Del returns a new map without k, or m if k isn't in it
+func (m *keyedMap[K, V]) Del(k K) *keyedMap[K, V] {
+	b := m.bucket(k)
+	for i, e := range b {
+		if e.k != k {
+			continue
+		}
+
+		if len(b) == 1 {
+			n, _ := m.m.Del(k)
+			return &keyedMap[K, V]{
+				m:    n,
+				size: m.size - 1,
+			}
+		}
+
+		rest := append(append(make([]keyedEntry[K, V], 0, len(b)-1), b[:i]...), b[i+1:]...)
+		return &keyedMap[K, V]{
+			m:    m.m.Put(k, rest),
+			size: m.size - 1,
+		}
+	}
+
+	return m
+}
+
+// Each runs f on every key and value in the map
+func (m *keyedMap[K, V]) Each(f func(K, V)) {
+	m.m.Each(func(_, x interface{}) {
+		for _, e := range x.([]keyedEntry[K, V]) {
+			f(e.k, e.v)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package immut
+
+import "testing"
+
+func TestKeyedMap(t *testing.T) {
+	a := printsSame{A: "a b", B: ""}
+	b := printsSame{A: "a", B: "b "}
+
+	m := newKeyedMap[printsSame, int]().Put(a, 1).Put(b, 2).Put(a, 3)
+	if m.Len() != 2 {
+		t.Fatalf("Expected 2 keys got %d", m.Len())
+	}
+
+	if v, found := m.Get(a); !found || v != 3 {
+		t.Errorf("Expected 3 got %d", v)
+	}
+
+	if v, found := m.Get(b); !found || v != 2 {
+		t.Errorf("Expected 2 got %d", v)
+	}
+
+	n := m.Del(a)
+	if _, found := n.Get(a); found || n.Len() != 1 {
+		t.Errorf("Expected a to be deleted, %d keys left", n.Len())
+	}
+
+	if v, found := n.Get(b); !found || v != 2 {
+		t.Errorf("Expected b to be kept, got %d", v)
+	}
+
+	if m.Del(printsSame{A: "c"}) != m {
+		t.Error("Expected deleting a missing key to return the map")
+	}
+
+	n = n.Del(b)
+	count := 0
+	n.Each(func(printsSame, int) {
+		count++
+	})
+	if n.Len() != 0 || count != 0 {
+		t.Errorf("Expected an empty map got %d keys", n.Len())
+	}
+}
@@ -0,0 +1,108 @@
+package immut
+
+// tombstone marks a key deleted in the overlay of a Layered map
+type tombstone struct{}
+
+// A Layered map stacks a small writable overlay over a shared base HashMap. Reads fall through
+// the overlay to the base, writes and deletes only ever touch the overlay, so many Layered maps
+// can share one large base.
+type Layered struct {
+	base    *HashMap
+	overlay *HashMap
+}
+
+// WithOverlay returns a Layered map with h as its base and an empty overlay
+func (h *HashMap) WithOverlay() *Layered {
+	return &Layered{
+		base:    h,
+		overlay: NewHashMap(),
+	}
+}
+
+// Base returns the map underneath the overlay
+func (l *Layered) Base() *HashMap {
+	return l.base
+}
+
+// Get returns the value stored at the given key, checking the overlay before the base
+func (l *Layered) Get(k interface{}) (interface{}, bool) {
+	if v, found := l.overlay.Get(k); found {
+		if _, deleted := v.(tombstone); deleted {
+			return nil, false
+		}
+
+		return v, true
+	}
+
+	return l.base.Get(k)
+}
+
+// Put stores the value in the overlay
+func (l *Layered) Put(k, v interface{}) *Layered {
+	return &Layered{
+		base:    l.base,
+		overlay: l.overlay.Put(k, v),
+	}
+}
+
+// Del hides the given key, leaving a tombstone in the overlay if the base holds it
+func (l *Layered) Del(k interface{}) *Layered {
+	if _, found := l.base.Get(k); !found {
+		overlay, _ := l.overlay.Del(k)
+		return &Layered{
+			base:    l.base,
+			overlay: overlay,
+		}
+	}
+
+	return &Layered{
+		base:    l.base,
+		overlay: l.overlay.Put(k, tombstone{}),
+	}
+}
+
+// Len returns the number of visible keys
+func (l *Layered) Len() int {
+	n := l.base.Len()
+	l.overlay.Each(func(k, v interface{}) {
+		_, inBase := l.base.Get(k)
+		_, deleted := v.(tombstone)
+		switch {
+		case deleted && inBase:
+			n--
+		case !deleted && !inBase:
+			n++
+		}
+	})
+
+	return n
+}
+
+// Each runs f on every visible k,v pair
+func (l *Layered) Each(f func(k, v interface{})) {
+	l.base.Each(func(k, v interface{}) {
+		if _, shadowed := l.overlay.Get(k); !shadowed {
+			f(k, v)
+		}
+	})
+
+	l.overlay.Each(func(k, v interface{}) {
+		if _, deleted := v.(tombstone); !deleted {
+			f(k, v)
+		}
+	})
+}
+
+// Flatten applies the overlay to the base and returns the resulting HashMap
+func (l *Layered) Flatten() *HashMap {
+	n := l.base
+	l.overlay.Each(func(k, v interface{}) {
+		if _, deleted := v.(tombstone); deleted {
+			n, _ = n.Del(k)
+		} else {
+			n = n.Put(k, v)
+		}
+	})
+
+	return n
+}
@@ -0,0 +1,61 @@
+package immut
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLayered(t *testing.T) {
+	Convey("Given a base map with an overlay", t, func() {
+		base := NewHashMap().Put("a", 1).Put("b", 2)
+		l := base.WithOverlay().Put("b", 20).Put("c", 30).Del("a")
+
+		Convey("Get should resolve through the layers", func() {
+			_, found := l.Get("a")
+			So(found, ShouldBeFalse)
+
+			v, _ := l.Get("b")
+			So(v, ShouldEqual, 20)
+
+			v, _ = l.Get("c")
+			So(v, ShouldEqual, 30)
+
+			So(l.Len(), ShouldEqual, 2)
+		})
+
+		Convey("The base should be untouched", func() {
+			v, _ := base.Get("a")
+			So(v, ShouldEqual, 1)
+
+			v, _ = base.Get("b")
+			So(v, ShouldEqual, 2)
+			So(base.Len(), ShouldEqual, 2)
+		})
+
+		Convey("Each should only visit visible pairs", func() {
+			seen := map[interface{}]interface{}{}
+			l.Each(func(k, v interface{}) {
+				seen[k] = v
+			})
+			So(seen, ShouldResemble, map[interface{}]interface{}{"b": 20, "c": 30})
+		})
+
+		Convey("Flatten should materialize the result", func() {
+			f := l.Flatten()
+			So(f.Len(), ShouldEqual, 2)
+
+			_, found := f.Get("a")
+			So(found, ShouldBeFalse)
+
+			v, _ := f.Get("b")
+			So(v, ShouldEqual, 20)
+		})
+
+		Convey("Deleting a key only in the overlay should not leave a tombstone", func() {
+			x := l.Del("c")
+			So(x.Len(), ShouldEqual, 1)
+			So(x.overlay.Len(), ShouldEqual, 2)
+		})
+	})
+}
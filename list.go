@@ -10,7 +10,8 @@ var (
 	IndexOutOfRange = errors.New("index out of range")
 )
 
-// A List is an immutable singly linked list that is safe for concurrent use
+// A List is an immutable singly linked list that is safe for concurrent use.
+// A nil *List is the empty list, every method can be called on it.
 type List struct {
 	next *List
 	val  interface{}
@@ -23,18 +24,53 @@ func NewList(val interface{}) *List {
 	}
 }
 
-// Val returns the value stored at the current node in the list
+// ListFromSlice builds a list holding the given values in the same order
+func ListFromSlice(vals []interface{}) *List {
+	var l *List
+	for i := len(vals) - 1; i >= 0; i-- {
+		l = l.Prepend(vals[i])
+	}
+
+	return l
+}
+
+// A ListBuilder builds a List in the order values are added to it. Adding is O(1), the
+// values are kept in reverse and put in order once by Build. The zero value is ready to use.
+type ListBuilder struct {
+	rev *List
+	n   int
+}
+
+// Add appends val to the list being built
+func (b *ListBuilder) Add(val interface{}) {
+	b.rev = b.rev.Prepend(val)
+	b.n++
+}
+
+// Len returns the number of values added so far
+func (b *ListBuilder) Len() int {
+	return b.n
+}
+
+// Build returns a list of every value added so far, in the order they were added. The builder
+// can keep being used afterwards, later values don't show up in lists already built.
+func (b *ListBuilder) Build() *List {
+	return b.rev.Reverse()
+}
+
+// Val returns the value stored at the current node in the list, nil for the empty list
 func (l *List) Val() interface{} {
+	if l == nil {
+		return nil
+	}
 	return l.val
 }
 
 // Len returns the length of the list
 func (l *List) Len() int {
-	i := 1
-	y := l
-	for !y.End() {
+	i := 0
+	for y := l; y != nil; y = y.next {
 		i++
-		y = y.next
 	}
 
 	return i
@@ -42,48 +78,52 @@ func (l *List) Len() int {
 
 // String returns a string representation of the list
 func (l *List) String() string {
-	if l == nil {
-		return "nil"
-	}
 	b := bytes.NewBuffer(nil)
 	b.WriteString("[")
-	y := l
-	for {
+	for y := l; y != nil; y = y.next {
 		b.WriteString(fmt.Sprintf("%v", y.val))
 		if !y.End() {
 			b.WriteString(", ")
-		} else {
-			break
 		}
-		y = y.next
 	}
 	b.WriteString("]")
 
 	return b.String()
 }
 
-// End returns true if this is the end of the list
+// End returns true if this is the last node in the list or the list is empty
 func (l *List) End() bool {
-	return l.next == nil
+	return l == nil || l.next == nil
 }
 
 // Index returns the value stored at the given index if it exists
 func (l *List) Index(i int) (interface{}, error) {
-	x := 0
-	y := l
-
-	for x < i {
+	if i < 0 {
+		return nil, IndexOutOfRange
+	}
 
-		if y.End() {
-			return nil, IndexOutOfRange
-		}
+	y := l
+	for x := 0; x < i && y != nil; x++ {
 		y = y.next
-		x++
+	}
+
+	if y == nil {
+		return nil, IndexOutOfRange
 	}
 
 	return y.val, nil
 }
 
+// ToSlice returns the values in the list in order
+func (l *List) ToSlice() []interface{} {
+	vals := make([]interface{}, 0, l.Len())
+	l.Each(func(i interface{}) {
+		vals = append(vals, i)
+	})
+
+	return vals
+}
+
 // Prepend the given value onto a new list
 func (l *List) Prepend(val interface{}) *List {
 	return &List{
@@ -92,8 +132,23 @@ func (l *List) Prepend(val interface{}) *List {
 	}
 }
 
-// Append the given value to the end of the list. This will reallocate the whole list
+// Reverse returns a new list with the values in reverse order
+func (l *List) Reverse() *List {
+	var r *List
+	l.Each(func(x interface{}) {
+		r = r.Prepend(x)
+	})
+
+	return r
+}
+
+// Append the given value to the end of the list. This will reallocate the whole list, so
+// building a list by appending one value at a time is quadratic. Use a ListBuilder or
+// ListFromSlice for that.
 func (l *List) Append(val interface{}) *List {
+	if l == nil {
+		return NewList(val)
+	}
 
 	// make a copy of this list
 	n := &List{}
@@ -113,18 +168,20 @@ func (l *List) Append(val interface{}) *List {
 
 // Next returns the next node in the list
 func (l *List) Next() *List {
+	if l == nil {
+		return nil
+	}
 	return l.next
 }
 
+// Each runs f on every value in the list in order
 func (l *List) Each(f func(i interface{})) {
-	if l == nil {
-		return
+	for y := l; y != nil; y = y.next {
+		f(y.val)
 	}
-
-	f(l.val)
-	l.Next().Each(f)
 }
 
+// Filter returns a list of the nodes for which f returns true
 func (l *List) Filter(f func(*List) bool) *List {
 	if l == nil {
 		return nil
@@ -133,14 +190,10 @@ func (l *List) Filter(f func(*List) bool) *List {
 	if f(l) {
 
 		n := NewList(l.val)
-		n.next = n.next.Filter(f)
+		n.next = l.next.Filter(f)
 		return n
 	}
 
-	if l.End() {
-		return nil
-	}
-
 	return l.next.Filter(f)
 
 }
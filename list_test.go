@@ -58,3 +58,116 @@ func TestListPrepend(t *testing.T) {
 	}
 
 }
+
+func TestListEmpty(t *testing.T) {
+	var l *List
+
+	if l.Len() != 0 {
+		t.Errorf("Expected 0 got %d", l.Len())
+	}
+
+	if l.String() != "[]" {
+		t.Errorf("Expected [] got %s", l.String())
+	}
+
+	if _, err := l.Index(0); err != IndexOutOfRange {
+		t.Errorf("Expected %v got %v", IndexOutOfRange, err)
+	}
+
+	l.Each(func(i interface{}) {
+		t.Errorf("Each should not run on an empty list, got %v", i)
+	})
+
+	if !l.End() || l.Next() != nil || l.Val() != nil {
+		t.Error("Empty list should be at its end")
+	}
+
+	x := l.Append(1)
+	if x.Len() != 1 {
+		t.Errorf("Expected 1 got %d", x.Len())
+	}
+}
+
+func TestListIndex(t *testing.T) {
+	l := ListFromSlice([]interface{}{0, 1, 2})
+
+	for i := 0; i < 3; i++ {
+		if v, err := l.Index(i); err != nil || v != i {
+			t.Errorf("Expected %d got %v, %v", i, v, err)
+		}
+	}
+
+	for _, i := range []int{-1, 3} {
+		if _, err := l.Index(i); err != IndexOutOfRange {
+			t.Errorf("Expected %v at %d got %v", IndexOutOfRange, i, err)
+		}
+	}
+}
+
+func TestListSlice(t *testing.T) {
+	vals := []interface{}{1, "two", 3.0}
+	l := ListFromSlice(vals)
+
+	if l.String() != "[1, two, 3]" {
+		t.Errorf("Expected [1, two, 3] got %s", l.String())
+	}
+
+	out := l.ToSlice()
+	if len(out) != len(vals) {
+		t.Fatalf("Expected %d got %d", len(vals), len(out))
+	}
+
+	for i := range vals {
+		if out[i] != vals[i] {
+			t.Errorf("Expected %v got %v", vals[i], out[i])
+		}
+	}
+
+	if ListFromSlice(nil) != nil {
+		t.Error("Empty slice should give the empty list")
+	}
+}
+
+func TestListFilter(t *testing.T) {
+	l := ListFromSlice([]interface{}{1, 2, 3, 4, 5, 6})
+	even := l.Filter(func(n *List) bool {
+		return n.Val().(int)%2 == 0
+	})
+
+	if even.String() != "[2, 4, 6]" {
+		t.Errorf("Expected [2, 4, 6] got %s", even)
+	}
+}
+
+func TestListBuilder(t *testing.T) {
+	var b ListBuilder
+	if b.Build() != nil {
+		t.Error("Expected an empty builder to build the empty list")
+	}
+
+	for i := 0; i < 5; i++ {
+		b.Add(i)
+	}
+
+	l := b.Build()
+	b.Add(5)
+
+	if l.String() != "[0, 1, 2, 3, 4]" {
+		t.Errorf("Unexpected list %s", l)
+	}
+
+	if b.Len() != 6 || b.Build().Len() != 6 {
+		t.Errorf("Expected 6 got %d", b.Len())
+	}
+}
+
+func BenchmarkListBuilder(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var lb ListBuilder
+		for j := 0; j < 1000; j++ {
+			lb.Add(j)
+		}
+		lb.Build()
+	}
+}
@@ -0,0 +1,144 @@
+package immut
+
+import "bytes"
+
+// An LWWMap is a last-writer-wins CRDT map. Every key remembers the timestamp of the write
+// that produced it, deletes leave a tombstone behind, and Merge keeps the newest write for each
+// key so replicas converge no matter what order their states are merged in.
+//
+// Timestamps are supplied by the caller. Anything that fits in an int64 and is monotonic per
+// replica works, e.g. unix nanos or a hybrid logical clock packed into 64 bits.
+type LWWMap struct {
+	entries *HashMap
+	size    int
+}
+
+// lwwEntry is the value stored in the underlying HashMap for every key
+type lwwEntry struct {
+	val     interface{}
+	ts      int64
+	deleted bool
+}
+
+// newer reports whether e wins over o. Ties on the timestamp go to deletes, then to the value
+// with the larger byte representation, so every replica picks the same winner.
+func (e lwwEntry) newer(o lwwEntry) bool {
+	if e.ts != o.ts {
+		return e.ts > o.ts
+	}
+
+	if e.deleted != o.deleted {
+		return e.deleted
+	}
+
+	return bytes.Compare(iToBytes(e.val), iToBytes(o.val)) > 0
+}
+
+// NewLWWMap returns an empty LWWMap
+func NewLWWMap() *LWWMap {
+	return &LWWMap{
+		entries: NewHashMap(),
+	}
+}
+
+// Len returns the number of live (not deleted) keys in the map
+func (l *LWWMap) Len() int {
+	return l.size
+}
+
+// Set stores v at k if ts is newer than the last write to k
+func (l *LWWMap) Set(k, v interface{}, ts int64) *LWWMap {
+	return l.apply(k, lwwEntry{val: v, ts: ts})
+}
+
+// Delete leaves a tombstone at k if ts is newer than the last write to k
+func (l *LWWMap) Delete(k interface{}, ts int64) *LWWMap {
+	return l.apply(k, lwwEntry{ts: ts, deleted: true})
+}
+
+// Get returns the live value stored at k, or nil, false if it is missing or deleted
+func (l *LWWMap) Get(k interface{}) (interface{}, bool) {
+	e, found := l.entries.Get(k)
+	if !found || e.(lwwEntry).deleted {
+		return nil, false
+	}
+
+	return e.(lwwEntry).val, true
+}
+
+// Timestamp returns the timestamp of the last write or delete seen for k
+func (l *LWWMap) Timestamp(k interface{}) (int64, bool) {
+	e, found := l.entries.Get(k)
+	if !found {
+		return 0, false
+	}
+
+	return e.(lwwEntry).ts, true
+}
+
+// Each runs f on every live k,v pair
+func (l *LWWMap) Each(f func(k, v interface{})) {
+	l.entries.Each(func(k, v interface{}) {
+		e := v.(lwwEntry)
+		if !e.deleted {
+			f(k, e.val)
+		}
+	})
+}
+
+// Merge combines two replicas, keeping the newest write for every key. Merge is commutative,
+// associative and idempotent, so it is safe to apply in any order.
+func (l *LWWMap) Merge(o *LWWMap) *LWWMap {
+	n := l
+	o.entries.Each(func(k, v interface{}) {
+		n = n.apply(k, v.(lwwEntry))
+	})
+
+	return n
+}
+
+// GC drops tombstones older than before. Only collect tombstones every replica has already
+// seen, otherwise a stale write can be resurrected by a later Merge.
+func (l *LWWMap) GC(before int64) *LWWMap {
+	n := l.entries
+	l.entries.Each(func(k, v interface{}) {
+		e := v.(lwwEntry)
+		if e.deleted && e.ts < before {
+			n, _ = n.Del(k)
+		}
+	})
+
+	if n == l.entries {
+		return l
+	}
+
+	return &LWWMap{
+		entries: n,
+		size:    l.size,
+	}
+}
+
+// apply stores e at k if it wins over whatever is there already
+func (l *LWWMap) apply(k interface{}, e lwwEntry) *LWWMap {
+	size := l.size
+	old, found := l.entries.Get(k)
+	if found {
+		o := old.(lwwEntry)
+		if !e.newer(o) {
+			return l
+		}
+
+		if !o.deleted {
+			size--
+		}
+	}
+
+	if !e.deleted {
+		size++
+	}
+
+	return &LWWMap{
+		entries: l.entries.Put(k, e),
+		size:    size,
+	}
+}
@@ -0,0 +1,90 @@
+package immut
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLWWMapSetDelete(t *testing.T) {
+	Convey("Given an LWWMap with a value set", t, func() {
+		l := NewLWWMap().Set("a", 1, 10)
+
+		Convey("An older write should be ignored", func() {
+			x := l.Set("a", 2, 5)
+			v, _ := x.Get("a")
+			So(v, ShouldEqual, 1)
+		})
+
+		Convey("A newer delete should remove the value", func() {
+			x := l.Delete("a", 11)
+			_, found := x.Get("a")
+			So(found, ShouldBeFalse)
+			So(x.Len(), ShouldEqual, 0)
+
+			Convey("And the previous version should still have it", func() {
+				v, found := l.Get("a")
+				So(found, ShouldBeTrue)
+				So(v, ShouldEqual, 1)
+				So(l.Len(), ShouldEqual, 1)
+			})
+		})
+
+		Convey("A delete at the same timestamp should win", func() {
+			x := l.Delete("a", 10)
+			_, found := x.Get("a")
+			So(found, ShouldBeFalse)
+		})
+	})
+}
+
+func TestLWWMapMerge(t *testing.T) {
+	a := NewLWWMap().Set("x", "a", 1).Set("y", "a", 5).Delete("z", 3)
+	b := NewLWWMap().Set("x", "b", 2).Set("y", "b", 4).Set("z", "b", 2).Set("w", "b", 1)
+
+	ab := a.Merge(b)
+	ba := b.Merge(a)
+
+	want := map[string]interface{}{"x": "b", "y": "a", "w": "b"}
+	for _, m := range []*LWWMap{ab, ba, ab.Merge(ab), ab.Merge(a).Merge(b)} {
+		if m.Len() != len(want) {
+			t.Errorf("Expected %d got %d", len(want), m.Len())
+		}
+
+		for k, v := range want {
+			if got, _ := m.Get(k); got != v {
+				t.Errorf("Expected %v at %s got %v", v, k, got)
+			}
+		}
+
+		if _, found := m.Get("z"); found {
+			t.Error("z should have stayed deleted")
+		}
+	}
+
+	// equal timestamps must resolve the same way on both sides
+	c := NewLWWMap().Set("k", "c", 7)
+	d := NewLWWMap().Set("k", "d", 7)
+	x, _ := c.Merge(d).Get("k")
+	y, _ := d.Merge(c).Get("k")
+	if x != y {
+		t.Errorf("Merge did not converge: %v != %v", x, y)
+	}
+}
+
+func TestLWWMapGC(t *testing.T) {
+	l := NewLWWMap().Set("a", 1, 1).Delete("a", 2).Delete("b", 8)
+
+	x := l.GC(5)
+	if _, found := x.Timestamp("a"); found {
+		t.Error("Tombstone for a should have been collected")
+	}
+
+	if _, found := x.Timestamp("b"); !found {
+		t.Error("Tombstone for b is too new to be collected")
+	}
+
+	if _, found := l.Timestamp("a"); !found {
+		t.Error("GC should not modify the previous version")
+	}
+}
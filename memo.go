@@ -0,0 +1,112 @@
+package immut
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// MemoPanicked is returned to callers that were waiting on a memoized call that panicked
+	MemoPanicked = errors.New("memoized function panicked")
+)
+
+// Memo returns a function that caches the result of f for every key it is called with.
+// Concurrent calls for a key that is not cached yet share a single call to f.
+func Memo[K comparable, V any](f func(K) V) func(K) V {
+	m := MemoErr(func(_ context.Context, k K) (V, error) {
+		return f(k), nil
+	})
+
+	return func(k K) V {
+		v, _ := m(context.Background(), k)
+		return v
+	}
+}
+
+// MemoErr is Memo for functions that can fail. Errors are returned to every caller waiting on
+// the call that produced them but are not cached. A caller whose ctx is done stops waiting and
+// gets ctx.Err(); the shared call itself runs with the ctx of the caller that started it.
+func MemoErr[K comparable, V any](f func(context.Context, K) (V, error)) func(context.Context, K) (V, error) {
+	m := &memo[K, V]{
+		f:     f,
+		calls: make(map[K]*memoCall[V]),
+	}
+	m.cache.Store(newKeyedMap[K, V]())
+
+	return m.get
+}
+
+// memo holds the cache for a memoized function. Reads go through the atomic pointer without
+// locking, mu only guards the in flight calls and swapping in a new cache. The cache compares
+// keys with ==, the same as calls, so keys that only print the same never share a result.
+type memo[K comparable, V any] struct {
+	f     func(context.Context, K) (V, error)
+	cache atomic.Pointer[keyedMap[K, V]]
+	mu    sync.Mutex
+	calls map[K]*memoCall[V]
+}
+
+// memoCall is a call to f that other callers can wait on
+type memoCall[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+}
+
+func (m *memo[K, V]) get(ctx context.Context, k K) (V, error) {
+	if v, found := m.cache.Load().Get(k); found {
+		return v, nil
+	}
+
+	m.mu.Lock()
+	if v, found := m.cache.Load().Get(k); found {
+		m.mu.Unlock()
+		return v, nil
+	}
+
+	if c, inFlight := m.calls[k]; inFlight {
+		m.mu.Unlock()
+		return c.wait(ctx)
+	}
+
+	c := &memoCall[V]{
+		done: make(chan struct{}),
+	}
+	m.calls[k] = c
+	m.mu.Unlock()
+
+	m.call(ctx, k, c)
+	return c.val, c.err
+}
+
+// call runs f for k and publishes the result to the cache and anyone waiting on c
+func (m *memo[K, V]) call(ctx context.Context, k K, c *memoCall[V]) {
+	finished := false
+	defer func() {
+		m.mu.Lock()
+		if !finished {
+			c.err = MemoPanicked
+		} else if c.err == nil {
+			m.cache.Store(m.cache.Load().Put(k, c.val))
+		}
+		delete(m.calls, k)
+		m.mu.Unlock()
+		close(c.done)
+	}()
+
+	c.val, c.err = m.f(ctx, k)
+	finished = true
+}
+
+// wait blocks until the call is done or ctx is
+func (c *memoCall[V]) wait(ctx context.Context) (V, error) {
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		var v V
+		return v, ctx.Err()
+	}
+}
@@ -0,0 +1,147 @@
+package immut
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMemo(t *testing.T) {
+	var calls int32
+	f := Memo(func(i int) int {
+		atomic.AddInt32(&calls, 1)
+		return i * 2
+	})
+
+	for i := 0; i < 3; i++ {
+		if v := f(21); v != 42 {
+			t.Errorf("Expected 42 got %d", v)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call got %d", calls)
+	}
+}
+
+func TestMemoSingleFlight(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	f := Memo(func(s string) string {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return s + s
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v := f("a"); v != "aa" {
+				t.Errorf("Expected aa got %s", v)
+			}
+		}()
+	}
+
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call got %d", calls)
+	}
+}
+
+func TestMemoErr(t *testing.T) {
+	var calls int32
+	fail := errors.New("fail")
+	f := MemoErr(func(_ context.Context, i int) (int, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return 0, fail
+		}
+		return i, nil
+	})
+
+	if _, err := f(context.Background(), 1); err != fail {
+		t.Errorf("Expected %v got %v", fail, err)
+	}
+
+	// errors are not cached
+	if v, err := f(context.Background(), 1); err != nil || v != 1 {
+		t.Errorf("Expected 1 got %d, %v", v, err)
+	}
+}
+
+func TestMemoErrCancel(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	f := MemoErr(func(_ context.Context, i int) (int, error) {
+		close(started)
+		<-release
+		return i, nil
+	})
+
+	go f(context.Background(), 1)
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f(ctx, 1); err != context.Canceled {
+		t.Errorf("Expected %v got %v", context.Canceled, err)
+	}
+	close(release)
+}
+
+func TestMemoPanic(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	m := &memo[int, int]{
+		f: func(_ context.Context, i int) (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		},
+		calls: make(map[int]*memoCall[int]),
+	}
+	m.cache.Store(newKeyedMap[int, int]())
+
+	go func() {
+		defer func() { recover() }()
+		m.get(context.Background(), 1)
+	}()
+	<-started
+
+	m.mu.Lock()
+	c := m.calls[1]
+	m.mu.Unlock()
+
+	close(release)
+	if _, err := c.wait(context.Background()); err != MemoPanicked {
+		t.Errorf("Expected %v got %v", MemoPanicked, err)
+	}
+}
+
+// printsSame keys print the same with fmt.Sprint, which is how a HashMap keys structs
+type printsSame struct {
+	A, B string
+}
+
+func TestMemoCollidingKeys(t *testing.T) {
+	var calls int32
+	f := Memo(func(k printsSame) string {
+		atomic.AddInt32(&calls, 1)
+		return k.A + "|" + k.B
+	})
+
+	a := printsSame{A: "a b", B: ""}
+	b := printsSame{A: "a", B: "b "}
+	if f(a) != "a b|" || f(b) != "a|b " || f(a) != "a b|" {
+		t.Errorf("Expected keys that print the same to be cached apart, got %q and %q", f(a), f(b))
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected 2 calls got %d", calls)
+	}
+}
@@ -0,0 +1,74 @@
+package immut
+
+import "reflect"
+
+// A Conflict is a key that two descendants of a common base changed in different ways. A and B
+// are the changes each side made to it relative to the base.
+type Conflict struct {
+	Key interface{}
+	A   Change
+	B   Change
+}
+
+// Merge3 merges the changes a and b each made to their common ancestor base. Keys only one side
+// changed, or both changed the same way, are merged automatically. Keys the two changed
+// differently are left at their value in base and returned as conflicts for the caller to
+// resolve. Both diffs skip the subtrees shared with base, so merging two small edits of a large
+// map only looks at the edited paths.
+func Merge3(base, a, b *HashMap) (*HashMap, []Conflict) {
+	ca := base.Diff(a)
+	cb := base.Diff(b)
+
+	// nothing to merge if either side is unchanged
+	if len(ca) == 0 {
+		return b, nil
+	}
+	if len(cb) == 0 {
+		return a, nil
+	}
+
+	byKey := NewHashMap()
+	for _, c := range ca {
+		byKey = byKey.Put(c.Key, c)
+	}
+
+	var conflicts []Conflict
+	merged := a
+	for _, c := range cb {
+		x, found := byKey.Get(c.Key)
+		if !found {
+			merged = applyChange(merged, c)
+			continue
+		}
+
+		other := x.(Change)
+		if other.Kind == c.Kind && (c.Kind == Removed || reflect.DeepEqual(other.New, c.New)) {
+			continue
+		}
+
+		// put the base value back until the conflict is resolved
+		if c.Kind == Added {
+			merged, _ = merged.Del(c.Key)
+		} else {
+			merged = merged.Put(c.Key, c.Old)
+		}
+
+		conflicts = append(conflicts, Conflict{
+			Key: c.Key,
+			A:   other,
+			B:   c,
+		})
+	}
+
+	return merged, conflicts
+}
+
+// applyChange makes the change c to h
+func applyChange(h *HashMap, c Change) *HashMap {
+	if c.Kind == Removed {
+		h, _ = h.Del(c.Key)
+		return h
+	}
+
+	return h.Put(c.Key, c.New)
+}
@@ -0,0 +1,75 @@
+package immut
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	base := NewHashMap()
+	for i := 0; i < 100; i++ {
+		base = base.Put(i, i)
+	}
+
+	// a and b make separate edits, agree on 3 and disagree on 4, 5 and 6
+	a := base.Put(1, "a").Put(3, "same").Put(4, "a").Put(100, "a").Put(6, "a")
+	a, _ = a.Del(5)
+	b := base.Put(2, "b").Put(3, "same").Put(4, "b").Put(5, "b").Put(101, "b")
+	b, _ = b.Del(6)
+	b, _ = b.Del(0)
+
+	merged, conflicts := Merge3(base, a, b)
+	if len(conflicts) != 3 {
+		t.Fatalf("Expected 3 conflicts got %v", conflicts)
+	}
+
+	for _, c := range conflicts {
+		switch c.Key {
+		case 4:
+			if c.A.New != "a" || c.B.New != "b" {
+				t.Errorf("Unexpected conflict %v", c)
+			}
+		case 5:
+			if c.A.Kind != Removed || c.B.Kind != Modified {
+				t.Errorf("Unexpected conflict %v", c)
+			}
+		case 6:
+			if c.A.Kind != Modified || c.B.Kind != Removed {
+				t.Errorf("Unexpected conflict %v", c)
+			}
+		default:
+			t.Errorf("Unexpected conflict on %v", c.Key)
+		}
+	}
+
+	want := base.Put(1, "a").Put(2, "b").Put(3, "same").Put(100, "a").Put(101, "b")
+	want, _ = want.Del(0)
+	if d := want.Diff(merged); len(d) != 0 {
+		t.Errorf("Unexpected merge result %v", d)
+	}
+}
+
+func TestMerge3Unchanged(t *testing.T) {
+	base := NewHashMap().Put("a", 1)
+	a := base.Put("b", 2)
+
+	if m, c := Merge3(base, a, base); m != a || c != nil {
+		t.Error("Expected a when b is unchanged")
+	}
+
+	if m, c := Merge3(base, base, a); m != a || c != nil {
+		t.Error("Expected b when a is unchanged")
+	}
+}
+
+func TestMerge3Added(t *testing.T) {
+	base := NewHashMap()
+	a := base.Put("k", 1)
+	b := base.Put("k", 2)
+
+	merged, conflicts := Merge3(base, a, b)
+	if len(conflicts) != 1 || conflicts[0].A.Kind != Added {
+		t.Fatalf("Expected a conflict on k got %v", conflicts)
+	}
+
+	if _, found := merged.Get("k"); found {
+		t.Error("Expected k to be missing like in the base")
+	}
+}
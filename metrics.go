@@ -0,0 +1,48 @@
+package immut
+
+import "sync/atomic"
+
+// Events reported to a MetricsSink
+const (
+	// EventNodeAlloc is a new trie node
+	EventNodeAlloc = "node_alloc"
+
+	// EventPathCopy is a trie node copied on the way to a change
+	EventPathCopy = "path_copy"
+
+	// EventCollision is a key added to the linear bucket at TrieMaxDepth, which holds every key
+	// with the same hash
+	EventCollision = "collision"
+)
+
+// A MetricsSink is told about allocations inside the package's structures, so the garbage
+// they produce can be attributed to the workloads that caused it. Observe is called on the hot
+// path of every update, from any goroutine, so it has to be cheap and safe for concurrent use.
+type MetricsSink interface {
+	Observe(event string, n int)
+}
+
+// sinkHolder wraps a MetricsSink so it can be stored in an atomic.Pointer
+type sinkHolder struct {
+	s MetricsSink
+}
+
+var metricsSink atomic.Pointer[sinkHolder]
+
+// SetMetricsSink starts sending events to s, replacing any sink set before. Passing nil stops
+// sending events. No sink is set by default.
+func SetMetricsSink(s MetricsSink) {
+	if s == nil {
+		metricsSink.Store(nil)
+		return
+	}
+
+	metricsSink.Store(&sinkHolder{s})
+}
+
+// observe reports an event to the sink if there is one
+func observe(event string, n int) {
+	if h := metricsSink.Load(); h != nil {
+		h.s.Observe(event, n)
+	}
+}
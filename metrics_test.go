@@ -0,0 +1,71 @@
+package immut
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// countingSink adds up every event it is told about
+type countingSink struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newCountingSink() *countingSink {
+	return &countingSink{
+		counts: map[string]int{},
+	}
+}
+
+func (c *countingSink) Observe(event string, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[event] += n
+}
+
+func (c *countingSink) get(event string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[event]
+}
+
+func TestMetricsSink(t *testing.T) {
+	m := newCountingSink()
+	SetMetricsSink(m)
+	defer SetMetricsSink(nil)
+
+	x := NewTrie()
+	for i := 0; i < 100; i++ {
+		x = x.Put([]byte(fmt.Sprint(i)), i)
+	}
+
+	for _, event := range []string{EventNodeAlloc, EventPathCopy} {
+		if m.get(event) == 0 {
+			t.Errorf("Expected %s to be counted", event)
+		}
+	}
+
+	SetMetricsSink(nil)
+	before := m.get(EventPathCopy)
+	x.Put([]byte("more"), nil)
+
+	if after := m.get(EventPathCopy); after != before {
+		t.Errorf("Expected no events after removing the sink, got %d more", after-before)
+	}
+}
+
+func TestMetricsCollision(t *testing.T) {
+	m := newCountingSink()
+	SetMetricsSink(m)
+	defer SetMetricsSink(nil)
+
+	x := NewTrieWithHasher(constHasher{})
+	for i := 0; i < 100; i++ {
+		x = x.Put([]byte(fmt.Sprint(i)), i)
+	}
+
+	if m.get(EventCollision) == 0 {
+		t.Error("Expected keys with the same hash to be counted as collisions")
+	}
+}
@@ -0,0 +1,109 @@
+package immut
+
+// A NestedMap maps a pair of keys to a value through a HashMap of HashMaps, e.g. a namespace to
+// the keys inside it. Inner maps are created the first time a key is put into them and dropped
+// once their last key is deleted, so an outer key is only present while it has entries.
+type NestedMap struct {
+	outer *HashMap
+	size  int
+}
+
+// NewNestedMap returns an empty NestedMap
+func NewNestedMap() *NestedMap {
+	return &NestedMap{
+		outer: NewHashMap(),
+	}
+}
+
+// Len returns the number of values stored across all of the inner maps
+func (n *NestedMap) Len() int {
+	return n.size
+}
+
+// OuterLen returns the number of outer keys
+func (n *NestedMap) OuterLen() int {
+	return n.outer.Len()
+}
+
+// Get returns the value stored at k1, k2
+func (n *NestedMap) Get(k1, k2 interface{}) (interface{}, bool) {
+	inner, found := n.outer.Get(k1)
+	if !found {
+		return nil, false
+	}
+
+	return inner.(*HashMap).Get(k2)
+}
+
+// Inner returns the map stored at k1
+func (n *NestedMap) Inner(k1 interface{}) (*HashMap, bool) {
+	inner, found := n.outer.Get(k1)
+	if !found {
+		return nil, false
+	}
+
+	return inner.(*HashMap), true
+}
+
+// Put maps k1, k2 to v, creating the inner map at k1 if it is missing
+func (n *NestedMap) Put(k1, k2, v interface{}) *NestedMap {
+	inner, found := n.Inner(k1)
+	if !found {
+		inner = NewHashMap()
+	}
+
+	before := inner.Len()
+	inner = inner.Put(k2, v)
+
+	return &NestedMap{
+		outer: n.outer.Put(k1, inner),
+		size:  n.size + inner.Len() - before,
+	}
+}
+
+// Del deletes the value stored at k1, k2 and returns it, dropping the inner map at k1 if it is
+// left empty
+func (n *NestedMap) Del(k1, k2 interface{}) (*NestedMap, interface{}) {
+	inner, found := n.Inner(k1)
+	if !found {
+		return n, nil
+	}
+
+	if _, found := inner.Get(k2); !found {
+		return n, nil
+	}
+
+	inner, v := inner.Del(k2)
+	outer := n.outer.Put(k1, inner)
+	if inner.Len() == 0 {
+		outer, _ = n.outer.Del(k1)
+	}
+
+	return &NestedMap{
+		outer: outer,
+		size:  n.size - 1,
+	}, v
+}
+
+// DelOuter deletes k1 and every value stored under it
+func (n *NestedMap) DelOuter(k1 interface{}) *NestedMap {
+	inner, found := n.Inner(k1)
+	if !found {
+		return n
+	}
+
+	outer, _ := n.outer.Del(k1)
+	return &NestedMap{
+		outer: outer,
+		size:  n.size - inner.Len(),
+	}
+}
+
+// Each runs f on every k1, k2, v triple
+func (n *NestedMap) Each(f func(k1, k2, v interface{})) {
+	n.outer.Each(func(k1, inner interface{}) {
+		inner.(*HashMap).Each(func(k2, v interface{}) {
+			f(k1, k2, v)
+		})
+	})
+}
@@ -0,0 +1,48 @@
+package immut
+
+import "testing"
+
+func TestNestedMap(t *testing.T) {
+	n := NewNestedMap().Put("ns1", "a", 1).Put("ns1", "b", 2).Put("ns2", "a", 3)
+
+	if n.Len() != 3 || n.OuterLen() != 2 {
+		t.Fatalf("Expected 3 values in 2 maps got %d in %d", n.Len(), n.OuterLen())
+	}
+
+	if v, _ := n.Get("ns2", "a"); v != 3 {
+		t.Errorf("Expected 3 got %v", v)
+	}
+
+	if _, found := n.Get("ns3", "a"); found {
+		t.Error("Expected a missing outer key to be missing")
+	}
+
+	if n.Put("ns1", "a", 10).Len() != 3 {
+		t.Error("Expected overwriting to keep the size")
+	}
+
+	d, v := n.Del("ns2", "a")
+	if v != 3 || d.Len() != 2 || d.OuterLen() != 1 {
+		t.Errorf("Expected the empty inner map to be dropped, got %v, %d, %d", v, d.Len(), d.OuterLen())
+	}
+
+	if same, _ := n.Del("ns1", "missing"); same != n {
+		t.Error("Expected deleting a missing key to return the same map")
+	}
+
+	if d := n.DelOuter("ns1"); d.Len() != 1 || d.OuterLen() != 1 {
+		t.Errorf("Expected 1 value left got %d", d.Len())
+	}
+
+	count := 0
+	n.Each(func(k1, k2, v interface{}) {
+		if x, _ := n.Get(k1, k2); x != v {
+			t.Errorf("Expected %v at %v, %v got %v", v, k1, k2, x)
+		}
+		count++
+	})
+
+	if count != 3 {
+		t.Errorf("Expected 3 got %d", count)
+	}
+}
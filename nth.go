@@ -0,0 +1,103 @@
+package immut
+
+// Nth returns the i-th k,v pair in the trie's traversal order, the same order Each uses. It
+// skips whole subtrees using their counts, so it runs in time proportional to the depth of the
+// trie rather than to i.
+func (t *Trie) Nth(i int) ([]byte, interface{}, bool) {
+	e, found := t.root.nth(i)
+	return e.rawKey, e.value, found
+}
+
+// EachSkip runs f on at most limit k,v pairs in traversal order, starting at the offset-th.
+// Subtrees before the offset are skipped by their counts, so paging deep into a large trie
+// doesn't walk everything in front of the page.
+func (t *Trie) EachSkip(offset, limit int, f func([]byte, interface{})) {
+	t.root.eachFrom(offset, limit, func(e Entry) {
+		f(e.rawKey, e.value)
+	})
+}
+
+// Nth returns the i-th k,v pair in the map's traversal order like Trie.Nth
+func (h *HashMap) Nth(i int) (interface{}, interface{}, bool) {
+	e, found := h.t.root.nth(i)
+	if !found {
+		return nil, nil, false
+	}
+
+	p := e.value.(Pair)
+	return p.Key, p.Val, true
+}
+
+// EachSkip runs f on at most limit k,v pairs in traversal order, starting at the offset-th, like
+// Trie.EachSkip
+func (h *HashMap) EachSkip(offset, limit int, f func(k, v interface{})) {
+	h.t.root.eachFrom(offset, limit, func(e Entry) {
+		p := e.value.(Pair)
+		f(p.Key, p.Val)
+	})
+}
+
+func (t *TNode) nth(i int) (Entry, bool) {
+	if i < 0 || i >= t.size {
+		return Entry{}, false
+	}
+
+	y := t
+	for {
+		if i < len(y.vals) {
+			return y.vals[i], true
+		}
+		i -= len(y.vals)
+
+		for _, c := range y.children {
+			if c == nil {
+				continue
+			}
+
+			if i < c.size {
+				y = c
+				break
+			}
+			i -= c.size
+		}
+	}
+}
+
+// eachFrom runs f on up to limit entries starting at the offset-th and returns how many it
+// visited
+func (t *TNode) eachFrom(offset, limit int, f func(Entry)) int {
+	if limit <= 0 || offset >= t.size {
+		return 0
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	visited := 0
+	for ; offset < len(t.vals) && visited < limit; offset++ {
+		f(t.vals[offset])
+		visited++
+	}
+
+	offset -= len(t.vals)
+	if offset < 0 {
+		offset = 0
+	}
+
+	for _, c := range t.children {
+		if c == nil || visited == limit {
+			continue
+		}
+
+		if offset >= c.size {
+			offset -= c.size
+			continue
+		}
+
+		visited += c.eachFrom(offset, limit-visited, f)
+		offset = 0
+	}
+
+	return visited
+}
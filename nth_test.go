@@ -0,0 +1,111 @@
+package immut
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTrieNth(t *testing.T) {
+	x := NewTrie()
+	for _, k := range randBytes(1000) {
+		x = x.Put(k, string(k))
+	}
+
+	i := 0
+	x.Each(func(k []byte, v interface{}) {
+		nk, nv, found := x.Nth(i)
+		if !found || !bytes.Equal(k, nk) || v != nv {
+			t.Fatalf("Expected %s at %d got %s", k, i, nk)
+		}
+		i++
+	})
+
+	if _, _, found := x.Nth(x.Size()); found {
+		t.Error("Nth past the end should fail")
+	}
+
+	if _, _, found := x.Nth(-1); found {
+		t.Error("Nth before the start should fail")
+	}
+}
+
+func TestTrieEachSkip(t *testing.T) {
+	x := NewTrie()
+	for _, k := range randBytes(1000) {
+		x = x.Put(k, nil)
+	}
+
+	var order [][]byte
+	x.Each(func(k []byte, _ interface{}) {
+		order = append(order, k)
+	})
+
+	for _, page := range [][2]int{{0, 10}, {5, 100}, {990, 50}, {1000, 10}, {300, 0}} {
+		var got [][]byte
+		x.EachSkip(page[0], page[1], func(k []byte, _ interface{}) {
+			got = append(got, k)
+		})
+
+		end := page[0] + page[1]
+		if end > len(order) {
+			end = len(order)
+		}
+
+		want := [][]byte{}
+		if page[0] < end {
+			want = order[page[0]:end]
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("Expected %d keys for page %v got %d", len(want), page, len(got))
+		}
+
+		for i := range want {
+			if !bytes.Equal(got[i], want[i]) {
+				t.Fatalf("Expected %s got %s", want[i], got[i])
+			}
+		}
+	}
+}
+
+func TestHashMapNth(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 100; i++ {
+		h = h.Put(i, i*3)
+	}
+
+	seen := map[interface{}]bool{}
+	for i := 0; i < h.Len(); i++ {
+		k, v, found := h.Nth(i)
+		if !found || v != k.(int)*3 {
+			t.Fatalf("Unexpected pair %v, %v at %d", k, v, i)
+		}
+		seen[k] = true
+	}
+
+	if len(seen) != 100 {
+		t.Errorf("Expected 100 distinct keys got %d", len(seen))
+	}
+
+	count := 0
+	h.EachSkip(95, 10, func(k, v interface{}) {
+		count++
+	})
+
+	if count != 5 {
+		t.Errorf("Expected 5 got %d", count)
+	}
+}
+
+func BenchmarkTrieEachSkipDeep(b *testing.B) {
+	x := NewTrie()
+	for _, k := range randBytes(10000) {
+		x = x.Put(k, nil)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.EachSkip(9000, 10, func([]byte, interface{}) {})
+	}
+}
@@ -0,0 +1,103 @@
+package immut
+
+// An Option holds a value or nothing. It is an alternative to the comma-ok results of lookups
+// that composes in chains of calls, e.g. h.GetOpt(k).OrElse(def).
+type Option[T any] struct {
+	val T
+	ok  bool
+}
+
+// Some returns an Option holding val
+func Some[T any](val T) Option[T] {
+	return Option[T]{
+		val: val,
+		ok:  true,
+	}
+}
+
+// None returns an empty Option
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// IsSome returns true if the Option holds a value
+func (o Option[T]) IsSome() bool {
+	return o.ok
+}
+
+// IsNone returns true if the Option is empty
+func (o Option[T]) IsNone() bool {
+	return !o.ok
+}
+
+// Get returns the value and whether there is one, the comma-ok form of the Option
+func (o Option[T]) Get() (T, bool) {
+	return o.val, o.ok
+}
+
+// OrElse returns the value, or def if the Option is empty
+func (o Option[T]) OrElse(def T) T {
+	if !o.ok {
+		return def
+	}
+
+	return o.val
+}
+
+// OrElseGet returns the value, or the result of f if the Option is empty. f is only called
+// when it is needed.
+func (o Option[T]) OrElseGet(f func() T) T {
+	if !o.ok {
+		return f()
+	}
+
+	return o.val
+}
+
+// Filter returns the Option if it holds a value pred returns true for, else an empty Option
+func (o Option[T]) Filter(pred func(T) bool) Option[T] {
+	if !o.ok || !pred(o.val) {
+		return None[T]()
+	}
+
+	return o
+}
+
+// MapOption returns an Option holding f of o's value, or an empty Option if o is empty. Go
+// methods can't add type parameters, so this is a function rather than a method.
+func MapOption[T, U any](o Option[T], f func(T) U) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+
+	return Some(f(o.val))
+}
+
+// FlatMapOption returns the result of f on o's value, or an empty Option if o is empty
+func FlatMapOption[T, U any](o Option[T], f func(T) Option[U]) Option[U] {
+	if !o.ok {
+		return None[U]()
+	}
+
+	return f(o.val)
+}
+
+// GetOpt returns the value stored at k as an Option
+func (h *HashMap) GetOpt(k interface{}) Option[interface{}] {
+	v, found := h.Get(k)
+	if !found {
+		return None[interface{}]()
+	}
+
+	return Some(v)
+}
+
+// GetOpt returns the value at the given index as an Option
+func (v *Vector) GetOpt(index int) Option[interface{}] {
+	x, found := v.Get(index)
+	if !found {
+		return None[interface{}]()
+	}
+
+	return Some(x)
+}
@@ -0,0 +1,66 @@
+package immut
+
+import "testing"
+
+func TestOption(t *testing.T) {
+	some := Some(2)
+	none := None[int]()
+
+	if !some.IsSome() || some.IsNone() || none.IsSome() || !none.IsNone() {
+		t.Error("Unexpected IsSome/IsNone")
+	}
+
+	if some.OrElse(5) != 2 || none.OrElse(5) != 5 {
+		t.Error("Unexpected OrElse")
+	}
+
+	called := false
+	some.OrElseGet(func() int {
+		called = true
+		return 0
+	})
+
+	if called {
+		t.Error("Expected OrElseGet not to call f when there is a value")
+	}
+
+	double := func(x int) int { return x * 2 }
+	if v, ok := MapOption(some, double).Get(); !ok || v != 4 {
+		t.Errorf("Expected 4 got %d", v)
+	}
+
+	if MapOption(none, double).IsSome() {
+		t.Error("Expected mapping None to give None")
+	}
+
+	if some.Filter(func(x int) bool { return x > 2 }).IsSome() {
+		t.Error("Expected the filter to drop 2")
+	}
+
+	half := func(x int) Option[int] {
+		if x%2 != 0 {
+			return None[int]()
+		}
+		return Some(x / 2)
+	}
+
+	if v := FlatMapOption(some, half).OrElse(-1); v != 1 {
+		t.Errorf("Expected 1 got %d", v)
+	}
+}
+
+func TestHashMapGetOpt(t *testing.T) {
+	h := NewHashMap().Put("a", 1)
+
+	if h.GetOpt("a").OrElse(0) != 1 {
+		t.Error("Expected 1")
+	}
+
+	if h.GetOpt("b").OrElse(0) != 0 {
+		t.Error("Expected the default for a missing key")
+	}
+
+	if NewVector().Put(0, "x").GetOpt(1).IsSome() {
+		t.Error("Expected a missing index to be None")
+	}
+}
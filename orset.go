@@ -0,0 +1,140 @@
+package immut
+
+// An ORSet is an observed-remove CRDT set. Every Add carries a unique tag and Remove only
+// removes the tags it has observed, so an Add that is concurrent with a Remove survives a Merge.
+// Tags must be unique across all replicas, e.g. a replica id combined with a counter.
+type ORSet struct {
+	adds    *HashMap // element -> *HashMap of live tags
+	removes *HashMap // tag -> element it was removed from
+}
+
+// NewORSet returns an empty ORSet
+func NewORSet() *ORSet {
+	return &ORSet{
+		adds:    NewHashMap(),
+		removes: NewHashMap(),
+	}
+}
+
+// Len returns the number of elements in the set
+func (o *ORSet) Len() int {
+	return o.adds.Len()
+}
+
+// Contains returns true if the element is in the set
+func (o *ORSet) Contains(elem interface{}) bool {
+	_, found := o.adds.Get(elem)
+	return found
+}
+
+// Each runs f on every element in the set
+func (o *ORSet) Each(f func(elem interface{})) {
+	o.adds.Each(func(k, v interface{}) {
+		f(k)
+	})
+}
+
+// Elements returns all of the elements in the set
+func (o *ORSet) Elements() []interface{} {
+	return o.adds.Keys()
+}
+
+// Add inserts the element into the set under the given tag
+func (o *ORSet) Add(elem, tag interface{}) *ORSet {
+	if _, removed := o.removes.Get(tag); removed {
+		return o
+	}
+
+	return &ORSet{
+		adds:    o.adds.Put(elem, o.tags(elem).Put(tag, true)),
+		removes: o.removes,
+	}
+}
+
+// Remove removes the element by tombstoning every tag that has been observed for it
+func (o *ORSet) Remove(elem interface{}) *ORSet {
+	tags, found := o.adds.Get(elem)
+	if !found {
+		return o
+	}
+
+	removes := o.removes
+	for _, tag := range tags.(*HashMap).Keys() {
+		removes = removes.Put(tag, elem)
+	}
+
+	adds, _ := o.adds.Del(elem)
+	return &ORSet{
+		adds:    adds,
+		removes: removes,
+	}
+}
+
+// Merge combines two replicas. An element is in the result if either side holds a tag for it
+// that neither side has removed. Merge is commutative, associative and idempotent.
+func (o *ORSet) Merge(other *ORSet) *ORSet {
+	n := o
+	other.removes.Each(func(tag, elem interface{}) {
+		n = n.tombstone(tag, elem)
+	})
+
+	other.adds.Each(func(elem, tags interface{}) {
+		tags.(*HashMap).Each(func(tag, _ interface{}) {
+			n = n.Add(elem, tag)
+		})
+	})
+
+	return n
+}
+
+// Compact drops the tombstones for which drop returns true. Only drop tags every replica has
+// already seen removed, otherwise a later Merge can bring the element back.
+func (o *ORSet) Compact(drop func(tag, elem interface{}) bool) *ORSet {
+	removes := o.removes
+	o.removes.Each(func(tag, elem interface{}) {
+		if drop(tag, elem) {
+			removes, _ = removes.Del(tag)
+		}
+	})
+
+	if removes == o.removes {
+		return o
+	}
+
+	return &ORSet{
+		adds:    o.adds,
+		removes: removes,
+	}
+}
+
+// tags returns the live tags for the element, or an empty map if there are none
+func (o *ORSet) tags(elem interface{}) *HashMap {
+	tags, found := o.adds.Get(elem)
+	if !found {
+		return NewHashMap()
+	}
+
+	return tags.(*HashMap)
+}
+
+// tombstone records that tag was removed from elem and drops it from the live tags
+func (o *ORSet) tombstone(tag, elem interface{}) *ORSet {
+	if _, removed := o.removes.Get(tag); removed {
+		return o
+	}
+
+	adds := o.adds
+	if tags, found := o.adds.Get(elem); found {
+		t, _ := tags.(*HashMap).Del(tag)
+		if t.Len() == 0 {
+			adds, _ = adds.Del(elem)
+		} else {
+			adds = adds.Put(elem, t)
+		}
+	}
+
+	return &ORSet{
+		adds:    adds,
+		removes: o.removes.Put(tag, elem),
+	}
+}
@@ -0,0 +1,71 @@
+package immut
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestORSetAddRemove(t *testing.T) {
+	Convey("Given an ORSet with an element", t, func() {
+		o := NewORSet().Add("a", "r1:1")
+		So(o.Contains("a"), ShouldBeTrue)
+
+		Convey("Removing the element should drop it", func() {
+			x := o.Remove("a")
+			So(x.Contains("a"), ShouldBeFalse)
+			So(x.Len(), ShouldEqual, 0)
+			So(o.Contains("a"), ShouldBeTrue)
+
+			Convey("Re-adding under a removed tag should be ignored", func() {
+				So(x.Add("a", "r1:1").Contains("a"), ShouldBeFalse)
+			})
+
+			Convey("Re-adding under a fresh tag should work", func() {
+				So(x.Add("a", "r1:2").Contains("a"), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestORSetMerge(t *testing.T) {
+	base := NewORSet().Add("a", "r1:1").Add("b", "r1:2")
+
+	// replica 1 removes a, replica 2 concurrently re-adds it
+	r1 := base.Remove("a").Add("c", "r1:3")
+	r2 := base.Add("a", "r2:1").Remove("b")
+
+	for _, m := range []*ORSet{r1.Merge(r2), r2.Merge(r1), r1.Merge(r2).Merge(r1)} {
+		if !m.Contains("a") {
+			t.Error("Concurrent add of a should survive the merge")
+		}
+
+		if m.Contains("b") {
+			t.Error("b should have been removed")
+		}
+
+		if !m.Contains("c") {
+			t.Error("c should have been added")
+		}
+
+		if m.Len() != 2 {
+			t.Errorf("Expected 2 got %d", m.Len())
+		}
+	}
+}
+
+func TestORSetCompact(t *testing.T) {
+	o := NewORSet().Add("a", 1).Add("b", 2).Remove("a").Remove("b")
+
+	x := o.Compact(func(tag, elem interface{}) bool {
+		return elem == "a"
+	})
+
+	if x.removes.Len() != 1 {
+		t.Errorf("Expected 1 tombstone got %d", x.removes.Len())
+	}
+
+	if o.removes.Len() != 2 {
+		t.Errorf("Compact should not modify the previous version")
+	}
+}
@@ -0,0 +1,213 @@
+package immut
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PatchOpKind says what a single patch operation does to its key
+type PatchOpKind int
+
+const (
+	PatchSet PatchOpKind = iota
+	PatchDelete
+)
+
+// String returns the name of the operation, as used in a patch's JSON encoding
+func (k PatchOpKind) String() string {
+	switch k {
+	case PatchSet:
+		return "set"
+	case PatchDelete:
+		return "delete"
+	}
+
+	return "unknown"
+}
+
+// A PatchOp is a single operation in a patch. Val is unset for deletes.
+type PatchOp struct {
+	Kind PatchOpKind
+	Key  interface{}
+	Val  interface{}
+}
+
+// A Patch is an immutable batch of set and delete operations on a HashMap. Patches can be
+// shipped between services in place of full snapshots, applied, inverted to undo them and
+// composed into one.
+type Patch struct {
+	ops []PatchOp
+}
+
+// NewPatch returns an empty patch
+func NewPatch() *Patch {
+	return &Patch{}
+}
+
+// PatchOf returns a patch that makes the changes returned by HashMap.Diff, so that
+// h.Diff(o) applied to h gives a map equal to o
+func PatchOf(changes []Change) *Patch {
+	p := &Patch{
+		ops: make([]PatchOp, 0, len(changes)),
+	}
+
+	for _, c := range changes {
+		if c.Kind == Removed {
+			p.ops = append(p.ops, PatchOp{Kind: PatchDelete, Key: c.Key})
+			continue
+		}
+		p.ops = append(p.ops, PatchOp{Kind: PatchSet, Key: c.Key, Val: c.New})
+	}
+
+	return p
+}
+
+// Len returns the number of operations in the patch
+func (p *Patch) Len() int {
+	return len(p.ops)
+}
+
+// Ops returns a copy of the operations in the patch, in the order they are applied
+func (p *Patch) Ops() []PatchOp {
+	return append([]PatchOp(nil), p.ops...)
+}
+
+// Set returns a new patch that also maps k to v
+func (p *Patch) Set(k, v interface{}) *Patch {
+	return p.with(PatchOp{Kind: PatchSet, Key: k, Val: v})
+}
+
+// Delete returns a new patch that also deletes k
+func (p *Patch) Delete(k interface{}) *Patch {
+	return p.with(PatchOp{Kind: PatchDelete, Key: k})
+}
+
+// with returns a new patch with op added to the end. The full slice expression makes append
+// copy, so patches never share a backing array they could both append to.
+func (p *Patch) with(op PatchOp) *Patch {
+	return &Patch{
+		ops: append(p.ops[:len(p.ops):len(p.ops)], op),
+	}
+}
+
+// Apply returns the result of running every operation in the patch on h, in order
+func (p *Patch) Apply(h *HashMap) *HashMap {
+	for _, op := range p.ops {
+		if op.Kind == PatchDelete {
+			h, _ = h.Del(op.Key)
+			continue
+		}
+		h = h.Put(op.Key, op.Val)
+	}
+
+	return h
+}
+
+// Invert returns a patch that undoes p, so that p.Invert(base).Apply(p.Apply(base)) is equal to
+// base. Every key p touches is set back to its value in base, or deleted if base didn't have it.
+func (p *Patch) Invert(base *HashMap) *Patch {
+	inv := &Patch{}
+	seen := NewHashMap()
+	for _, op := range p.ops {
+		var added bool
+		if seen, added = seen.PutIfAbsent(op.Key, nil); !added {
+			continue
+		}
+
+		if v, found := base.Get(op.Key); found {
+			inv.ops = append(inv.ops, PatchOp{Kind: PatchSet, Key: op.Key, Val: v})
+			continue
+		}
+		inv.ops = append(inv.ops, PatchOp{Kind: PatchDelete, Key: op.Key})
+	}
+
+	return inv
+}
+
+// Compose returns a single patch that has the same effect as applying p and then o. Sets and
+// deletes don't depend on what was there before, so only the last operation on every key is
+// kept, in the position of the first.
+func (p *Patch) Compose(o *Patch) *Patch {
+	n := &Patch{
+		ops: make([]PatchOp, 0, len(p.ops)+len(o.ops)),
+	}
+
+	index := NewHashMap()
+	for _, ops := range [][]PatchOp{p.ops, o.ops} {
+		for _, op := range ops {
+			if i, found := index.Get(op.Key); found {
+				n.ops[i.(int)] = op
+				continue
+			}
+
+			index = index.Put(op.Key, len(n.ops))
+			n.ops = append(n.ops, op)
+		}
+	}
+
+	return n
+}
+
+// patchJSON is the JSON encoding of a single patch operation
+type patchJSON struct {
+	Op  string      `json:"op"`
+	Key interface{} `json:"key"`
+	Val interface{} `json:"val,omitempty"`
+}
+
+// MarshalJSON encodes the patch as a JSON array of {"op", "key", "val"} objects
+func (p *Patch) MarshalJSON() ([]byte, error) {
+	ops := make([]patchJSON, len(p.ops))
+	for i, op := range p.ops {
+		ops[i] = patchJSON{
+			Op:  op.Kind.String(),
+			Key: op.Key,
+			Val: op.Val,
+		}
+	}
+
+	return json.Marshal(ops)
+}
+
+// UnmarshalJSON decodes a patch encoded by MarshalJSON. Keys and values are decoded the same way
+// json.Unmarshal decodes into an interface{}, so numeric keys come back as float64.
+func (p *Patch) UnmarshalJSON(data []byte) error {
+	var ops []patchJSON
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return err
+	}
+
+	n := &Patch{
+		ops: make([]PatchOp, len(ops)),
+	}
+
+	for i, op := range ops {
+		switch op.Op {
+		case PatchSet.String():
+			n.ops[i] = PatchOp{Kind: PatchSet, Key: op.Key, Val: op.Val}
+		case PatchDelete.String():
+			n.ops[i] = PatchOp{Kind: PatchDelete, Key: op.Key}
+		default:
+			return fmt.Errorf("immut: unknown patch operation %q", op.Op)
+		}
+	}
+
+	*p = *n
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder
+func (p *Patch) GobEncode() ([]byte, error) {
+	return encodeGob(p.ops)
+}
+
+// GobDecode implements gob.GobDecoder
+func (p *Patch) GobDecode(data []byte) error {
+	var ops []PatchOp
+	if err := decodeGob(data, &ops); err != nil {
+		return err
+	}
+
+	p.ops = ops
+	return nil
+}
@@ -0,0 +1,111 @@
+package immut
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPatchApply(t *testing.T) {
+	base := NewHashMap().Put("a", 1).Put("b", 2)
+	p := NewPatch().Set("a", 10).Delete("b").Set("c", 3)
+
+	n := p.Apply(base)
+	want := NewHashMap().Put("a", 10).Put("c", 3)
+	if !n.Equal(want) {
+		t.Errorf("Unexpected result %v", n.Entries())
+	}
+
+	if base.Len() != 2 {
+		t.Error("Expected the base map to be untouched")
+	}
+
+	if q := p.Set("d", 4); p.Len() != 3 || q.Len() != 4 {
+		t.Errorf("Expected Set to leave the patch untouched, got %d and %d", p.Len(), q.Len())
+	}
+}
+
+func TestPatchInvert(t *testing.T) {
+	base := NewHashMap().Put("a", 1).Put("b", 2)
+	p := NewPatch().Set("a", 10).Set("a", 11).Delete("b").Set("c", 3)
+
+	undo := p.Invert(base)
+	if undo.Len() != 3 {
+		t.Errorf("Expected one operation per key got %d", undo.Len())
+	}
+
+	if back := undo.Apply(p.Apply(base)); !back.Equal(base) {
+		t.Errorf("Expected the inverse to restore the base, got %v", back.Entries())
+	}
+}
+
+func TestPatchCompose(t *testing.T) {
+	base := NewHashMap().Put("a", 1).Put("b", 2)
+	p := NewPatch().Set("a", 10).Delete("b")
+	o := NewPatch().Set("b", 20).Delete("a").Set("c", 3)
+
+	c := p.Compose(o)
+	if c.Len() != 3 {
+		t.Errorf("Expected 3 operations got %d", c.Len())
+	}
+
+	if !c.Apply(base).Equal(o.Apply(p.Apply(base))) {
+		t.Error("Expected the composed patch to match applying both")
+	}
+
+	if ops := c.Ops(); ops[0].Key != "a" || ops[0].Kind != PatchDelete {
+		t.Errorf("Expected the last operation on a in its first position, got %v", ops[0])
+	}
+}
+
+func TestPatchOf(t *testing.T) {
+	a := NewHashMap().Put("a", 1).Put("b", 2)
+	b := NewHashMap().Put("a", 10).Put("c", 3)
+
+	if !PatchOf(a.Diff(b)).Apply(a).Equal(b) {
+		t.Error("Expected the patch of a diff to turn a into b")
+	}
+}
+
+func TestPatchJSON(t *testing.T) {
+	p := NewPatch().Set("a", 1).Delete("b")
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `[{"op":"set","key":"a","val":1},{"op":"delete","key":"b"}]` {
+		t.Errorf("Unexpected JSON %s", b)
+	}
+
+	out := NewPatch()
+	if err := json.Unmarshal(b, out); err != nil {
+		t.Fatal(err)
+	}
+
+	if ops := out.Ops(); len(ops) != 2 || ops[0].Val != 1.0 || ops[1].Kind != PatchDelete {
+		t.Errorf("Unexpected operations %v", ops)
+	}
+
+	if err := json.Unmarshal([]byte(`[{"op":"move","key":"a"}]`), out); err == nil {
+		t.Error("Expected an error for an unknown operation")
+	}
+}
+
+func TestPatchGob(t *testing.T) {
+	p := NewPatch().Set("a", 1).Delete(2)
+
+	b, err := p.GobEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := NewPatch()
+	if err := out.GobDecode(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if !out.Apply(NewHashMap().Put(2, 2)).Equal(NewHashMap().Put("a", 1)) {
+		t.Errorf("Unexpected operations %v", out.Ops())
+	}
+}
@@ -0,0 +1,43 @@
+package immut
+
+// Any returns true if pred returns true for any k,v pair in the map. It stops at the first match.
+func (h *HashMap) Any(pred func(k, v interface{}) bool) bool {
+	_, _, found := h.Find(pred)
+	return found
+}
+
+// Every returns true if pred returns true for every k,v pair in the map. It stops at the first
+// pair that fails.
+func (h *HashMap) Every(pred func(k, v interface{}) bool) bool {
+	return h.t.root.eachUntil(func(e Entry) bool {
+		p := e.value.(Pair)
+		return pred(p.Key, p.Val)
+	})
+}
+
+// CountWhere returns the number of k,v pairs in the map for which pred returns true
+func (h *HashMap) CountWhere(pred func(k, v interface{}) bool) int {
+	n := 0
+	h.Each(func(k, v interface{}) {
+		if pred(k, v) {
+			n++
+		}
+	})
+
+	return n
+}
+
+// Find returns the first k,v pair in the map for which pred returns true
+func (h *HashMap) Find(pred func(k, v interface{}) bool) (interface{}, interface{}, bool) {
+	var match Pair
+	found := !h.t.root.eachUntil(func(e Entry) bool {
+		p := e.value.(Pair)
+		if pred(p.Key, p.Val) {
+			match = p
+			return false
+		}
+		return true
+	})
+
+	return match.Key, match.Val, found
+}
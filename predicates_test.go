@@ -0,0 +1,70 @@
+package immut
+
+import "testing"
+
+func TestHashMapPredicates(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 100; i++ {
+		h = h.Put(i, i*2)
+	}
+
+	even := func(k, v interface{}) bool {
+		return k.(int)%2 == 0
+	}
+
+	if !h.Any(even) {
+		t.Error("Expected some even keys")
+	}
+
+	if h.Every(even) {
+		t.Error("Expected some odd keys")
+	}
+
+	if !h.Every(func(k, v interface{}) bool { return v == k.(int)*2 }) {
+		t.Error("Expected every value to be twice its key")
+	}
+
+	if n := h.CountWhere(even); n != 50 {
+		t.Errorf("Expected 50 got %d", n)
+	}
+
+	k, v, found := h.Find(func(k, v interface{}) bool { return v == 42 })
+	if !found || k != 21 || v != 42 {
+		t.Errorf("Expected 21, 42 got %v, %v, %t", k, v, found)
+	}
+
+	if _, _, found := h.Find(func(k, v interface{}) bool { return false }); found {
+		t.Error("Expected no match")
+	}
+}
+
+func TestHashMapPredicatesStopEarly(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 100; i++ {
+		h = h.Put(i, i)
+	}
+
+	calls := 0
+	h.Any(func(k, v interface{}) bool {
+		calls++
+		return true
+	})
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call got %d", calls)
+	}
+
+	calls = 0
+	h.Every(func(k, v interface{}) bool {
+		calls++
+		return false
+	})
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call got %d", calls)
+	}
+
+	if !NewHashMap().Every(func(k, v interface{}) bool { return false }) {
+		t.Error("Every should be true for an empty map")
+	}
+}
@@ -0,0 +1,94 @@
+package immut
+
+import "bytes"
+
+// RangeFrom runs f on every k,v pair that comes after the given key in the trie's traversal
+// order, the same order Each uses, until f returns false. Passing the last key seen lets a long
+// running job walk a snapshot in bounded slices.
+//
+// If the key is not in the trie, e.g. it was deleted since the last slice, the scan resumes
+// where the key would be. A deleted key leaves no trace of exactly where it sat, so if that could
+// have been a node along its path the scan resumes from the whole of that node. Nothing after
+// the key is skipped, but a few pairs may be visited twice.
+func (t *Trie) RangeFrom(after []byte, f func([]byte, interface{}) bool) {
+	t.root.rangeFrom(t.entry(after, nil), func(e Entry) bool {
+		return f(e.rawKey, e.value)
+	})
+}
+
+// RangeFrom runs f on every k,v pair that comes after the given key in the map's traversal
+// order like Trie.RangeFrom
+func (h *HashMap) RangeFrom(after interface{}, f func(k, v interface{}) bool) {
+	h.t.root.rangeFrom(h.t.entry(iToBytes(after), nil), func(e Entry) bool {
+		p := e.value.(Pair)
+		return f(p.Key, p.Val)
+	})
+}
+
+// rangeFrom runs f on every entry after e in traversal order. It walks down to e the way get
+// does, then unwinds, visiting whatever comes later at each level.
+func (t *TNode) rangeFrom(e Entry, f func(Entry) bool) {
+	var path []*TNode
+	var taken []uint32
+
+	// a small trie keeps its entries sorted by key, so a missing key still has a place
+	if t.depth == 0 && t.leaf() {
+		for _, v := range t.vals {
+			if bytes.Compare(v.rawKey, e.rawKey) > 0 && !f(v) {
+				return
+			}
+		}
+		return
+	}
+
+	y := t
+	found := false
+	for y != nil {
+		for p, v := range y.vals {
+			if !v.sameKey(e) {
+				continue
+			}
+
+			// the rest of this node comes next
+			for _, v := range y.vals[p+1:] {
+				if !f(v) {
+					return
+				}
+			}
+
+			for _, c := range y.children {
+				if c != nil && !c.eachUntil(f) {
+					return
+				}
+			}
+			found = true
+			break
+		}
+
+		if found {
+			break
+		}
+
+		// below the root a node is made with one entry, or a bucket of them at maxDepth, so one
+		// that lost it or a bucket may have held e
+		if y != t && (len(y.vals) == 0 || y.depth >= maxDepth) {
+			if !y.eachUntil(f) {
+				return
+			}
+			break
+		}
+
+		index := e.indexAtDepth(y.depth)
+		path = append(path, y)
+		taken = append(taken, index)
+		y = y.children[index]
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		for c := taken[i] + 1; c < width; c++ {
+			if x := path[i].children[c]; x != nil && !x.eachUntil(f) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package immut
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTrieRangeFrom(t *testing.T) {
+	x := NewTrie()
+	for _, k := range randBytes(1000) {
+		x = x.Put(k, nil)
+	}
+
+	var order [][]byte
+	x.Each(func(k []byte, _ interface{}) {
+		order = append(order, k)
+	})
+
+	for _, i := range []int{0, 1, 10, 500, 998, 999} {
+		var got [][]byte
+		x.RangeFrom(order[i], func(k []byte, _ interface{}) bool {
+			got = append(got, k)
+			return true
+		})
+
+		want := order[i+1:]
+		if len(got) != len(want) {
+			t.Fatalf("Expected %d keys after %d got %d", len(want), i, len(got))
+		}
+
+		for j := range want {
+			if !bytes.Equal(got[j], want[j]) {
+				t.Fatalf("Expected %s got %s", want[j], got[j])
+			}
+		}
+	}
+}
+
+func TestHashMapRangeFromSlices(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 1000; i++ {
+		h = h.Put(i, i)
+	}
+
+	// walk the whole map in slices of 100
+	seen := map[interface{}]bool{}
+	var last interface{}
+	h.Find(func(k, v interface{}) bool {
+		last = k
+		return true
+	})
+	seen[last] = true
+
+	for len(seen) < h.Len() {
+		n := 0
+		h.RangeFrom(last, func(k, v interface{}) bool {
+			if seen[k] {
+				t.Fatalf("%v visited twice", k)
+			}
+			seen[k] = true
+			last = k
+			n++
+			return n < 100
+		})
+
+		if n == 0 {
+			t.Fatalf("Scan stalled after %d keys", len(seen))
+		}
+	}
+}
+
+func TestHashMapRangeFromMissing(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 100; i++ {
+		h = h.Put(i, i)
+	}
+
+	// resuming after a deleted key should still visit every key that came after it
+	var order []interface{}
+	h.Each(func(k, v interface{}) {
+		order = append(order, k)
+	})
+
+	d, _ := h.Del(order[50])
+	seen := map[interface{}]bool{}
+	d.RangeFrom(order[50], func(k, v interface{}) bool {
+		seen[k] = true
+		return true
+	})
+
+	for _, k := range order[51:] {
+		if !seen[k] {
+			t.Errorf("Expected %v after the deleted key", k)
+		}
+	}
+}
+
+func TestTrieRangeFromNeverInserted(t *testing.T) {
+	for _, n := range []int{5, 2000} {
+		x := NewTrie()
+		for _, k := range randBytes(n) {
+			x = x.Put(k, nil)
+		}
+
+		// a key that was never there resumes exactly where putting it would place it
+		for _, k := range randBytes(20) {
+			if _, found := x.Get(k); found {
+				continue
+			}
+
+			var want, got [][]byte
+			x.Put(k, nil).RangeFrom(k, func(k []byte, _ interface{}) bool {
+				want = append(want, k)
+				return true
+			})
+			x.RangeFrom(k, func(k []byte, _ interface{}) bool {
+				got = append(got, k)
+				return true
+			})
+
+			if len(got) != len(want) {
+				t.Fatalf("Expected %d pairs after %s got %d", len(want), k, len(got))
+			}
+			for i := range want {
+				if !bytes.Equal(got[i], want[i]) {
+					t.Fatalf("Expected %s at %d got %s", want[i], i, got[i])
+				}
+			}
+		}
+	}
+}
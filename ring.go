@@ -0,0 +1,72 @@
+package immut
+
+// A Ring is a persistent ring buffer holding the last Cap values pushed onto it. Pushing onto a
+// full ring drops the oldest value. Every version shares its nodes with the one it was pushed
+// onto, which makes it cheap to keep the last N snapshots of something around.
+type Ring[T any] struct {
+	v        *Vector
+	capacity int
+}
+
+// NewRing returns an empty ring that holds up to capacity values. A capacity below 1 is
+// treated as 1.
+func NewRing[T any](capacity int) *Ring[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &Ring[T]{
+		v:        NewVector(),
+		capacity: capacity,
+	}
+}
+
+// Len returns the number of values in the ring
+func (r *Ring[T]) Len() int {
+	return r.v.Size()
+}
+
+// Cap returns the most values the ring holds
+func (r *Ring[T]) Cap() int {
+	return r.capacity
+}
+
+// Push returns a new ring with val added as the newest value, dropping the oldest value if the
+// ring is full
+func (r *Ring[T]) Push(val T) *Ring[T] {
+	n := &Ring[T]{
+		v:        r.v.Put(r.Len(), val),
+		capacity: r.capacity,
+	}
+
+	if n.Len() > n.capacity {
+		n.v = n.v.dropFirst()
+	}
+
+	return n
+}
+
+// At returns the i-th value in the ring, counting from the oldest
+func (r *Ring[T]) At(i int) (T, bool) {
+	var zero T
+	if i < 0 || i >= r.Len() {
+		return zero, false
+	}
+
+	x, found := r.v.Get(i)
+	if !found {
+		return zero, false
+	}
+
+	// a nil pushed into a ring of an interface type comes back as the zero T
+	t, _ := x.(T)
+	return t, true
+}
+
+// Each runs f on every value in the ring from the oldest to the newest
+func (r *Ring[T]) Each(f func(T)) {
+	for i := 0; i < r.Len(); i++ {
+		x, _ := r.At(i)
+		f(x)
+	}
+}
@@ -0,0 +1,57 @@
+package immut
+
+import "testing"
+
+func ringValues[T any](r *Ring[T]) []T {
+	var vals []T
+	r.Each(func(x T) {
+		vals = append(vals, x)
+	})
+
+	return vals
+}
+
+func TestRing(t *testing.T) {
+	r := NewRing[int](3)
+	if r.Len() != 0 || r.Cap() != 3 {
+		t.Fatalf("Expected an empty ring of 3 got %d of %d", r.Len(), r.Cap())
+	}
+
+	var versions []*Ring[int]
+	for i := 0; i < 10; i++ {
+		r = r.Push(i)
+		versions = append(versions, r)
+	}
+
+	if vals := ringValues(r); len(vals) != 3 || vals[0] != 7 || vals[2] != 9 {
+		t.Errorf("Expected [7 8 9] got %v", vals)
+	}
+
+	if vals := ringValues(versions[1]); len(vals) != 2 || vals[0] != 0 || vals[1] != 1 {
+		t.Errorf("Expected older versions to be untouched, got %v", vals)
+	}
+
+	if x, found := r.At(0); !found || x != 7 {
+		t.Errorf("Expected 7 got %v", x)
+	}
+
+	if _, found := r.At(3); found {
+		t.Error("Expected At past the end to fail")
+	}
+
+	if NewRing[string](0).Push("a").Push("b").Len() != 1 {
+		t.Error("Expected a capacity below 1 to hold 1 value")
+	}
+}
+
+func TestRingNil(t *testing.T) {
+	r := NewRing[error](2).Push(nil)
+	if x, found := r.At(0); !found || x != nil {
+		t.Errorf("Expected a stored nil got %v %t", x, found)
+	}
+
+	a := NewRing[any](2).Push(nil).Push(1)
+	if vals := ringValues(a); len(vals) != 2 || vals[0] != nil || vals[1] != 1 {
+		t.Errorf("Expected [nil 1] got %v", vals)
+	}
+}
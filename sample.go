@@ -0,0 +1,73 @@
+package immut
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+)
+
+// Sample returns n distinct k,v pairs chosen uniformly at random, in no particular order. Each
+// pair is found with Nth, which descends by the subtree counts, so sampling costs about
+// n * depth no matter how large the map is. If n is at least Len every pair is returned.
+func (h *HashMap) Sample(rng *rand.Rand, n int) []Pair {
+	size := h.Len()
+	if n <= 0 {
+		return nil
+	}
+	if n > size {
+		n = size
+	}
+
+	// Floyd's algorithm picks n distinct indexes in n steps
+	picked := make(map[int]bool, n)
+	pairs := make([]Pair, 0, n)
+	for j := size - n; j < size; j++ {
+		i := rng.Intn(j + 1)
+		if picked[i] {
+			i = j
+		}
+		picked[i] = true
+
+		k, v, _ := h.Nth(i)
+		pairs = append(pairs, Pair{Key: k, Val: v})
+	}
+
+	return pairs
+}
+
+// SampleWeighted returns n distinct k,v pairs chosen at random with chances in proportion to
+// their weight, in no particular order. Pairs with a weight of 0 or less are never chosen. The
+// weights aren't stored in the trie, so it makes one pass over the map keeping a heap of n
+// pairs, and never copies the map into a slice.
+func (h *HashMap) SampleWeighted(rng *rand.Rand, n int, weight func(k, v interface{}) float64) []Pair {
+	if n <= 0 {
+		return nil
+	}
+
+	// every pair gets the score log(u)/weight and the n highest scores win, see Efraimidis and
+	// Spirakis, "Weighted random sampling with a reservoir"
+	p := &pairHeap{less: func(a, b Pair) bool {
+		return a.Key.(float64) < b.Key.(float64)
+	}}
+	h.Each(func(k, v interface{}) {
+		w := weight(k, v)
+		if !(w > 0) {
+			return
+		}
+
+		x := Pair{Key: math.Log(1-rng.Float64()) / w, Val: Pair{Key: k, Val: v}}
+		if len(p.pairs) < n {
+			heap.Push(p, x)
+		} else if p.less(p.pairs[0], x) {
+			p.pairs[0] = x
+			heap.Fix(p, 0)
+		}
+	})
+
+	pairs := make([]Pair, len(p.pairs))
+	for i, x := range p.pairs {
+		pairs[i] = x.Val.(Pair)
+	}
+
+	return pairs
+}
@@ -0,0 +1,74 @@
+package immut
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := NewHashMap()
+	for i := 0; i < 100; i++ {
+		h = h.Put(i, i*2)
+	}
+
+	counts := make([]int, 100)
+	for i := 0; i < 2000; i++ {
+		pairs := h.Sample(r, 5)
+		if len(pairs) != 5 {
+			t.Fatalf("Expected 5 pairs got %d", len(pairs))
+		}
+
+		seen := map[interface{}]bool{}
+		for _, p := range pairs {
+			if seen[p.Key] || p.Val != p.Key.(int)*2 {
+				t.Fatalf("Expected distinct pairs from the map, got %v", pairs)
+			}
+			seen[p.Key] = true
+			counts[p.Key.(int)]++
+		}
+	}
+
+	// every key is expected 100 times
+	for k, c := range counts {
+		if c < 50 || c > 150 {
+			t.Errorf("Expected %d to be picked about 100 times, got %d", k, c)
+		}
+	}
+
+	if pairs := h.Sample(r, 500); len(pairs) != 100 {
+		t.Errorf("Expected every pair got %d", len(pairs))
+	}
+
+	if pairs := NewHashMap().Sample(r, 3); len(pairs) != 0 {
+		t.Errorf("Expected nothing from an empty map got %v", pairs)
+	}
+}
+
+func TestSampleWeighted(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := NewHashMap().Put("never", 0).Put("rare", 1).Put("often", 9)
+
+	counts := map[interface{}]int{}
+	for i := 0; i < 5000; i++ {
+		pairs := h.SampleWeighted(r, 1, func(k, v interface{}) float64 {
+			return float64(v.(int))
+		})
+		if len(pairs) != 1 {
+			t.Fatalf("Expected 1 pair got %d", len(pairs))
+		}
+		counts[pairs[0].Key]++
+	}
+
+	if counts["never"] != 0 {
+		t.Errorf("Expected pairs weighing 0 never to be picked, got %d", counts["never"])
+	}
+
+	if c := counts["rare"]; c < 350 || c > 650 {
+		t.Errorf("Expected rare to be picked about 500 times, got %d", c)
+	}
+
+	if pairs := h.SampleWeighted(r, 5, func(k, v interface{}) float64 { return 1 }); len(pairs) != 3 {
+		t.Errorf("Expected every pair got %d", len(pairs))
+	}
+}
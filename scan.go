@@ -0,0 +1,81 @@
+package immut
+
+import (
+	"bytes"
+	"sort"
+)
+
+// The trie is laid out by key hash, so every ordered scan below walks every entry, keeps the
+// ones in range and sorts those. That makes a scan O(n + m log m), where m is the number of
+// keys in range.
+
+// sortedEntries returns the entries keep returns true for in lexicographic order of their keys
+func (t *Trie) sortedEntries(keep func(Entry) bool) []Entry {
+	var entries []Entry
+	t.root.eachEntry(func(e Entry) {
+		if keep(e) {
+			entries = append(entries, e)
+		}
+	})
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].rawKey, entries[j].rawKey) < 0
+	})
+
+	return entries
+}
+
+// scanSorted runs f on the entries keep returns true for in lexicographic order, until f
+// returns false
+func (t *Trie) scanSorted(keep func(Entry) bool, f func([]byte, interface{}) bool) {
+	for _, e := range t.sortedEntries(keep) {
+		if !f(e.rawKey, e.value) {
+			return
+		}
+	}
+}
+
+// EachOrdered runs f on every k,v pair in lexicographic order of the keys
+func (t *Trie) EachOrdered(f func([]byte, interface{})) {
+	t.scanSorted(func(Entry) bool {
+		return true
+	}, func(k []byte, v interface{}) bool {
+		f(k, v)
+		return true
+	})
+}
+
+// Seek runs f on every k,v pair with a key >= start in lexicographic order, until f returns
+// false
+func (t *Trie) Seek(start []byte, f func([]byte, interface{}) bool) {
+	t.ScanRange(start, nil, f)
+}
+
+// ScanRange runs f on every k,v pair with a key in [start, end) in lexicographic order, until
+// f returns false. A nil end scans to the last key.
+func (t *Trie) ScanRange(start, end []byte, f func([]byte, interface{}) bool) {
+	t.scanSorted(func(e Entry) bool {
+		return bytes.Compare(e.rawKey, start) >= 0 && (end == nil || bytes.Compare(e.rawKey, end) < 0)
+	}, f)
+}
+
+// ScanPrefix runs f on every k,v pair whose key starts with prefix in lexicographic order,
+// until f returns false
+func (t *Trie) ScanPrefix(prefix []byte, f func([]byte, interface{}) bool) {
+	t.scanSorted(func(e Entry) bool {
+		return bytes.HasPrefix(e.rawKey, prefix)
+	}, f)
+}
+
+// eachEntry runs f on every entry in the node and it's children
+func (t *TNode) eachEntry(f func(Entry)) {
+	for _, e := range t.vals {
+		f(e)
+	}
+
+	for i := 0; i < len(t.children); i++ {
+		if t.children[i] != nil {
+			t.children[i].eachEntry(f)
+		}
+	}
+}
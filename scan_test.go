@@ -0,0 +1,136 @@
+package immut
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/eliothedeman/immut/keycodec"
+)
+
+func scanTrie() *Trie {
+	x := NewTrie()
+	for _, k := range []string{"b", "a", "ab", "abc", "b1", "c", "aa", "ba"} {
+		x = x.Put([]byte(k), k)
+	}
+
+	return x
+}
+
+func collect(scan func(f func([]byte, interface{}) bool)) []string {
+	var keys []string
+	scan(func(k []byte, v interface{}) bool {
+		keys = append(keys, string(k))
+		return true
+	})
+
+	return keys
+}
+
+func expectKeys(t *testing.T, want, got []string) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("Expected %v got %v", want, got)
+	}
+
+	for i := range want {
+		if want[i] != got[i] {
+			t.Fatalf("Expected %v got %v", want, got)
+		}
+	}
+}
+
+func TestTrieEachOrdered(t *testing.T) {
+	x := NewTrie()
+	for _, k := range randBytes(1000) {
+		x = x.Put(k, nil)
+	}
+
+	var last []byte
+	count := 0
+	x.EachOrdered(func(k []byte, v interface{}) {
+		if last != nil && bytes.Compare(last, k) >= 0 {
+			t.Fatalf("%s came after %s", k, last)
+		}
+		last = k
+		count++
+	})
+
+	if count != x.Size() {
+		t.Errorf("Expected %d got %d", x.Size(), count)
+	}
+}
+
+func TestTrieSeek(t *testing.T) {
+	x := scanTrie()
+	expectKeys(t, []string{"abc", "b", "b1", "ba", "c"}, collect(func(f func([]byte, interface{}) bool) {
+		x.Seek([]byte("abb"), f)
+	}))
+}
+
+func TestTrieScanRange(t *testing.T) {
+	x := scanTrie()
+	expectKeys(t, []string{"ab", "abc", "b"}, collect(func(f func([]byte, interface{}) bool) {
+		x.ScanRange([]byte("ab"), []byte("b1"), f)
+	}))
+
+	expectKeys(t, []string{"ba", "c"}, collect(func(f func([]byte, interface{}) bool) {
+		x.ScanRange([]byte("b2"), nil, f)
+	}))
+}
+
+func TestTrieScanPrefix(t *testing.T) {
+	x := scanTrie()
+	expectKeys(t, []string{"a", "aa", "ab", "abc"}, collect(func(f func([]byte, interface{}) bool) {
+		x.ScanPrefix([]byte("a"), f)
+	}))
+
+	// stop early
+	count := 0
+	x.ScanPrefix([]byte("b"), func(k []byte, v interface{}) bool {
+		count++
+		return count < 2
+	})
+
+	if count != 2 {
+		t.Errorf("Expected 2 got %d", count)
+	}
+}
+
+func TestTrieScanRangeKeycodec(t *testing.T) {
+	x := NewTrie()
+	for i := int64(-50); i < 50; i++ {
+		x = x.Put(keycodec.AppendInt64(nil, i), i)
+	}
+
+	var got []int64
+	x.ScanRange(keycodec.AppendInt64(nil, -3), keycodec.AppendInt64(nil, 3), func(k []byte, v interface{}) bool {
+		got = append(got, v.(int64))
+		return true
+	})
+
+	if len(got) != 6 {
+		t.Fatalf("Expected 6 got %v", got)
+	}
+
+	for i, v := range got {
+		if v != int64(i-3) {
+			t.Errorf("Expected %d got %d", i-3, v)
+		}
+	}
+}
+
+func TestSortedEntriesFilters(t *testing.T) {
+	x := NewTrie()
+	for i := 0; i < 1000; i++ {
+		x = x.Put([]byte(fmt.Sprintf("%04d", i)), i)
+	}
+
+	entries := x.sortedEntries(func(e Entry) bool {
+		return bytes.HasPrefix(e.rawKey, []byte("012"))
+	})
+
+	if len(entries) != 10 || string(entries[0].rawKey) != "0120" || string(entries[9].rawKey) != "0129" {
+		t.Errorf("Expected only the 10 keys in range, sorted, got %d", len(entries))
+	}
+}
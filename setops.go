@@ -0,0 +1,94 @@
+package immut
+
+// Union returns a map with every k,v pair in h and others. When a key is in more than one map
+// the value from the last of them wins.
+func (h *HashMap) Union(others ...*HashMap) *HashMap {
+	return UnionAll(append([]*HashMap{h}, others...)...)
+}
+
+// UnionAll returns a map with every k,v pair in ms, the value from the last map holding a key
+// winning. The result is built on top of the largest map so it shares as much as it can, and
+// every other map is only walked once.
+func UnionAll(ms ...*HashMap) *HashMap {
+	if len(ms) == 0 {
+		return NewHashMap()
+	}
+
+	largest := 0
+	for i, m := range ms {
+		if m.Len() > ms[largest].Len() {
+			largest = i
+		}
+	}
+
+	n := ms[largest]
+
+	// maps before the largest one lose to it, the later ones of them first
+	for i := largest - 1; i >= 0; i-- {
+		ms[i].Each(func(k, v interface{}) {
+			n, _ = n.PutIfAbsent(k, v)
+		})
+	}
+
+	for _, m := range ms[largest+1:] {
+		m.Each(func(k, v interface{}) {
+			n = n.Put(k, v)
+		})
+	}
+
+	return n
+}
+
+// Intersect returns the k,v pairs of h whose keys are also in every one of others
+func (h *HashMap) Intersect(others ...*HashMap) *HashMap {
+	return h.RetainWhere(func(k, _ interface{}) bool {
+		for _, o := range others {
+			if _, found := o.Get(k); !found {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// IntersectAll returns the k,v pairs of the first map whose keys are in every map in ms
+func IntersectAll(ms ...*HashMap) *HashMap {
+	if len(ms) == 0 {
+		return NewHashMap()
+	}
+
+	return ms[0].Intersect(ms[1:]...)
+}
+
+// Difference returns the k,v pairs of h whose keys aren't in any of others. If others hold
+// fewer keys than h their keys are deleted one by one, otherwise h is filtered in a single walk.
+func (h *HashMap) Difference(others ...*HashMap) *HashMap {
+	total := 0
+	for _, o := range others {
+		total += o.Len()
+	}
+
+	if total >= h.Len() {
+		return h.DeleteWhere(func(k, _ interface{}) bool {
+			for _, o := range others {
+				if _, found := o.Get(k); found {
+					return true
+				}
+			}
+
+			return false
+		})
+	}
+
+	n := h
+	for _, o := range others {
+		o.Each(func(k, _ interface{}) {
+			if _, found := n.Get(k); found {
+				n, _ = n.Del(k)
+			}
+		})
+	}
+
+	return n
+}
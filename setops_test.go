@@ -0,0 +1,83 @@
+package immut
+
+import "testing"
+
+func rangeMap(from, to int, val interface{}) *HashMap {
+	h := NewHashMap()
+	for i := from; i < to; i++ {
+		h = h.Put(i, val)
+	}
+
+	return h
+}
+
+func TestUnionAll(t *testing.T) {
+	a := rangeMap(0, 10, "a")
+	b := rangeMap(5, 100, "b")
+	c := rangeMap(90, 110, "c")
+
+	u := UnionAll(a, b, c)
+	if u.Len() != 110 {
+		t.Fatalf("Expected 110 got %d", u.Len())
+	}
+
+	for k, want := range map[int]string{0: "a", 5: "b", 50: "b", 95: "c", 105: "c"} {
+		if v, _ := u.Get(k); v != want {
+			t.Errorf("Expected %s at %d got %v", want, k, v)
+		}
+	}
+
+	if !a.Union(b, c).Equal(u) {
+		t.Error("Expected Union to match UnionAll")
+	}
+
+	if UnionAll().Len() != 0 {
+		t.Error("Expected the union of nothing to be empty")
+	}
+
+	// later maps that are smaller than earlier ones still win
+	if v, _ := UnionAll(b, a).Get(5); v != "a" {
+		t.Errorf("Expected a got %v", v)
+	}
+}
+
+func TestIntersectAll(t *testing.T) {
+	a := rangeMap(0, 50, "a")
+	b := rangeMap(10, 100, "b")
+	c := rangeMap(40, 60, "c")
+
+	i := IntersectAll(a, b, c)
+	if i.Len() != 10 {
+		t.Fatalf("Expected 10 got %d", i.Len())
+	}
+
+	if v, _ := i.Get(45); v != "a" {
+		t.Errorf("Expected values from the first map, got %v", v)
+	}
+
+	if IntersectAll().Len() != 0 || IntersectAll(a).Len() != 50 {
+		t.Error("Unexpected intersection of zero or one maps")
+	}
+}
+
+func TestDifference(t *testing.T) {
+	a := rangeMap(0, 100, "a")
+
+	// few keys to remove
+	if d := a.Difference(rangeMap(0, 5, nil), rangeMap(95, 200, nil)); d.Len() != 90 {
+		t.Errorf("Expected 90 got %d", d.Len())
+	}
+
+	// many keys to remove
+	if d := a.Difference(rangeMap(-50, 50, nil)); d.Len() != 50 {
+		t.Errorf("Expected 50 got %d", d.Len())
+	}
+
+	if d := a.Difference(rangeMap(0, 2, nil)); d.Len() != 98 {
+		t.Errorf("Expected 98 got %d", d.Len())
+	}
+
+	if a.Difference() != a {
+		t.Error("Expected the difference with nothing to be the map itself")
+	}
+}
@@ -0,0 +1,168 @@
+package immut
+
+// Shard splits the trie into 2^n disjoint tries by the low n bits of the key hashes. Every shard
+// reuses the subtrees below its part of the hash directly instead of reinserting their entries,
+// so splitting only copies the nodes above them. n can be at most 32, the number of bits in a
+// hash, Shard panics above that.
+func (t *Trie) Shard(n uint) []*Trie {
+	if n > 32 {
+		panic("immut: can't shard by more than the 32 bits of a hash")
+	}
+
+	low := uint32(uint64(1)<<n - 1)
+	shards := make([]*Trie, 1<<n)
+	for i := range shards {
+		shards[i] = &Trie{
+			root:   t.root.shard(uint32(i), low),
+			hasher: t.hasher,
+		}
+	}
+
+	return shards
+}
+
+// shard returns the part of the node holding keys whose hash matches s in the bits set in low,
+// or nil if there are none below the root. A node at depth d only holds keys that already share
+// their lowest d*bits bits, so once low has nothing above those the whole node is shared.
+func (t *TNode) shard(s, low uint32) *TNode {
+	shift := t.depth * bits
+	if shift >= 32 || low>>shift == 0 {
+		return t
+	}
+
+	y := NewTNode(nil, nil)
+	y.depth = t.depth
+	for _, e := range t.vals {
+		if e.hashedKey&low == s {
+			y.vals = append(y.vals, e)
+			y.size++
+		}
+	}
+
+	for i, c := range t.children {
+		if c == nil || (uint32(i)^s>>shift)&(low>>shift)&mask != 0 {
+			continue
+		}
+
+		if n := c.shard(s, low); n != nil {
+			y.children[i] = n
+			y.size += n.size
+		}
+	}
+
+	if y.size == 0 && t.depth > 0 {
+		return nil
+	}
+
+	return y
+}
+
+// MergeShards joins tries produced by Shard back into one. Shards that don't overlap are joined
+// by reusing their subtrees, anything else falls back to putting every entry. The result uses
+// the first shard's Hasher.
+func MergeShards(shards ...*Trie) *Trie {
+	n := NewTrie()
+	if len(shards) > 0 {
+		n.hasher = shards[0].hasher
+	}
+
+	var overlapping []*Trie
+	for _, s := range shards {
+		if !n.sameHasher(s) {
+			overlapping = append(overlapping, s)
+			continue
+		}
+
+		root, ok := n.root.mergeDisjoint(s.root)
+		if !ok {
+			overlapping = append(overlapping, s)
+			continue
+		}
+		n.root = root
+	}
+
+	for _, s := range overlapping {
+		s.root.eachEntry(func(e Entry) {
+			n = n.Put(e.rawKey, e.value)
+		})
+	}
+
+	return n
+}
+
+// Shard splits the map into 2^n disjoint maps like Trie.Shard
+func (h *HashMap) Shard(n uint) []*HashMap {
+	tries := h.t.Shard(n)
+	shards := make([]*HashMap, len(tries))
+	for i, t := range tries {
+		shards[i] = &HashMap{
+			t: t,
+		}
+	}
+
+	return shards
+}
+
+// MergeHashMapShards joins maps produced by HashMap.Shard back into one
+func MergeHashMapShards(shards ...*HashMap) *HashMap {
+	tries := make([]*Trie, len(shards))
+	for i, s := range shards {
+		tries[i] = s.t
+	}
+
+	return &HashMap{
+		t: MergeShards(tries...),
+	}
+}
+
+// mergeDisjoint returns a node holding the entries of both nodes, reusing every subtree only
+// one of them has. It returns false if the two can't be joined that way: both hold entries in
+// the same node, a small root is involved, or a key in one is also in the other.
+func (t *TNode) mergeDisjoint(o *TNode) (*TNode, bool) {
+	if o.size == 0 {
+		return t, true
+	}
+	if t.size == 0 {
+		return o, true
+	}
+	if len(t.vals) > 0 && len(o.vals) > 0 || t.depth == 0 && len(t.vals)+len(o.vals) > 0 {
+		return nil, false
+	}
+
+	// keys along a shared path can sit at different depths in the two nodes
+	for _, e := range t.vals {
+		if _, found := o.get(e); found {
+			return nil, false
+		}
+	}
+	for _, e := range o.vals {
+		if _, found := t.get(e); found {
+			return nil, false
+		}
+	}
+
+	y := t.copy()
+	if len(o.vals) > 0 {
+		y.vals = o.vals
+	}
+	y.size += o.size
+
+	for i, c := range o.children {
+		if c == nil {
+			continue
+		}
+
+		if y.children[i] == nil {
+			y.children[i] = c
+			continue
+		}
+
+		n, ok := y.children[i].mergeDisjoint(c)
+		if !ok {
+			return nil, false
+		}
+		y.children[i] = n
+	}
+
+	return y, true
+}
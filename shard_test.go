@@ -0,0 +1,125 @@
+package immut
+
+import "testing"
+
+func TestTrieShard(t *testing.T) {
+	x := NewTrie()
+	keys := randBytes(2000)
+	for _, k := range keys {
+		x = x.Put(k, string(k))
+	}
+
+	for _, n := range []uint{0, 1, 2, 4, 6, 8, 12} {
+		shards := x.Shard(n)
+
+		want := 1 << n
+		if len(shards) != want {
+			t.Fatalf("Expected %d shards got %d", want, len(shards))
+		}
+
+		total := 0
+		for _, s := range shards {
+			total += s.Size()
+		}
+
+		if total != x.Size() {
+			t.Errorf("Expected %d entries across the shards got %d", x.Size(), total)
+		}
+
+		// every key lives in exactly one shard
+		for _, k := range keys[:100] {
+			found := 0
+			for _, s := range shards {
+				if v, ok := s.Get(k); ok && v == string(k) {
+					found++
+				}
+			}
+
+			if found != 1 {
+				t.Fatalf("Expected %s in one shard got %d", k, found)
+			}
+		}
+
+		m := MergeShards(shards...)
+		if m.Size() != x.Size() {
+			t.Errorf("Expected %d got %d", x.Size(), m.Size())
+		}
+
+		for _, k := range keys {
+			if _, ok := m.Get(k); !ok {
+				t.Fatalf("%s missing after merge", k)
+			}
+		}
+	}
+}
+
+func TestTrieShardDeep(t *testing.T) {
+	x := NewTrie()
+	keys := randBytes(2000)
+	for _, k := range keys {
+		x = x.Put(k, string(k))
+	}
+
+	shards := x.Shard(6)
+	for i, s := range shards {
+		s.root.eachEntry(func(e Entry) {
+			if e.hashedKey&63 != uint32(i) {
+				t.Fatalf("Expected %s in shard %d", e.rawKey, e.hashedKey&63)
+			}
+		})
+	}
+
+	// a shard passed twice can't be joined by reusing nodes, and must not be counted twice
+	m := MergeShards(append(shards, shards[3])...)
+	if m.Size() != x.Size() {
+		t.Errorf("Expected %d got %d", x.Size(), m.Size())
+	}
+
+	for _, k := range keys {
+		if v, _ := m.Get(k); v != string(k) {
+			t.Fatalf("Expected %s got %v", k, v)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected sharding by more than 32 bits to panic")
+		}
+	}()
+	x.Shard(33)
+}
+
+func TestMergeShardsOverlapping(t *testing.T) {
+	a := NewTrie().Put([]byte("a"), 1).Put([]byte("b"), 2)
+	b := NewTrie().Put([]byte("a"), 3).Put([]byte("c"), 4)
+
+	m := MergeShards(a, b)
+	if m.Size() != 3 {
+		t.Errorf("Expected 3 got %d", m.Size())
+	}
+
+	if v, _ := m.Get([]byte("a")); v != 3 {
+		t.Errorf("Expected the later shard to win, got %v", v)
+	}
+}
+
+func TestHashMapShard(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 500; i++ {
+		h = h.Put(i, i)
+	}
+
+	shards := h.Shard(2)
+	if len(shards) != 4 {
+		t.Fatalf("Expected 4 shards got %d", len(shards))
+	}
+
+	m := MergeHashMapShards(shards...)
+	if m.Len() != 500 {
+		t.Errorf("Expected 500 got %d", m.Len())
+	}
+
+	if v, _ := m.Get(250); v != 250 {
+		t.Errorf("Expected 250 got %v", v)
+	}
+}
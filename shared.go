@@ -0,0 +1,78 @@
+package immut
+
+import "reflect"
+
+var (
+	nodeBytes  = int(reflect.TypeOf(TNode{}).Size())
+	entryBytes = int(reflect.TypeOf(Entry{}).Size())
+)
+
+// Shared reports how many of o's nodes are shared with t, and how many nodes o has in total.
+// Versions derived from each other with Put and Del share everything except the paths they
+// changed, so a low ratio between two versions of the same data usually means one of them was
+// rebuilt from scratch.
+func (t *Trie) Shared(o *Trie) (shared, total int) {
+	return t.root.shared(o.root, func(*TNode) int {
+		return 1
+	})
+}
+
+// SharedBytes is like Shared but weighs every node by an estimate of the memory it holds, the
+// node itself and its entries, not counting the keys and values they point to.
+func (t *Trie) SharedBytes(o *Trie) (shared, total int) {
+	return t.root.shared(o.root, func(n *TNode) int {
+		return nodeBytes + cap(n.vals)*entryBytes
+	})
+}
+
+// Shared reports how many of b's trie nodes are shared with a, and how many nodes b has in
+// total, like Trie.Shared
+func Shared(a, b *HashMap) (shared, total int) {
+	return a.t.Shared(b.t)
+}
+
+// SharedBytes reports roughly how many bytes of b's trie are shared with a, and how many bytes
+// it holds in total, like Trie.SharedBytes
+func SharedBytes(a, b *HashMap) (shared, total int) {
+	return a.t.SharedBytes(b.t)
+}
+
+// shared walks t and o side by side. Nodes only ever sit at one position in the trie, so a
+// node of o can only be shared with the node of t at the same position, and once they are the
+// same node the whole subtree below is shared too.
+func (t *TNode) shared(o *TNode, weight func(*TNode) int) (shared, total int) {
+	if t == o {
+		o.eachNode(func(n *TNode) {
+			shared += weight(n)
+		})
+		return shared, shared
+	}
+
+	total = weight(o)
+	for i, c := range o.children {
+		if c == nil {
+			continue
+		}
+
+		var tc *TNode
+		if t != nil {
+			tc = t.children[i]
+		}
+
+		s, n := tc.shared(c, weight)
+		shared += s
+		total += n
+	}
+
+	return shared, total
+}
+
+// eachNode runs f on the node and all of its descendants
+func (t *TNode) eachNode(f func(*TNode)) {
+	f(t)
+	for _, c := range t.children {
+		if c != nil {
+			c.eachNode(f)
+		}
+	}
+}
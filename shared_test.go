@@ -0,0 +1,53 @@
+package immut
+
+import "testing"
+
+func TestTrieShared(t *testing.T) {
+	a := NewTrie()
+	for _, k := range randBytes(1000) {
+		a = a.Put(k, nil)
+	}
+
+	shared, total := a.Shared(a)
+	if shared != total || total == 0 {
+		t.Errorf("Expected a trie to share all of its %d nodes with itself, got %d", total, shared)
+	}
+
+	b := a.Put([]byte("one more"), nil)
+	shared, total = a.Shared(b)
+	if total-shared > maxDepth+2 {
+		t.Errorf("Expected only the changed path to differ, got %d of %d shared", shared, total)
+	}
+
+	rebuilt := NewTrie()
+	a.Each(func(k []byte, v interface{}) {
+		rebuilt = rebuilt.Put(k, v)
+	})
+
+	shared, _ = a.Shared(rebuilt)
+	if shared != 0 {
+		t.Errorf("Expected a rebuilt trie to share nothing, got %d", shared)
+	}
+
+	sharedBytes, totalBytes := a.SharedBytes(b)
+	if sharedBytes == 0 || sharedBytes >= totalBytes {
+		t.Errorf("Unexpected byte counts %d of %d", sharedBytes, totalBytes)
+	}
+}
+
+func TestHashMapShared(t *testing.T) {
+	a := NewHashMap()
+	for i := 0; i < 100; i++ {
+		a = a.Put(i, i)
+	}
+
+	b, _ := a.Del(50)
+	shared, total := Shared(a, b)
+	if shared == 0 || shared == total {
+		t.Errorf("Expected partial sharing, got %d of %d", shared, total)
+	}
+
+	if s, n := Shared(NewHashMap(), NewHashMap()); s != 0 || n != 1 {
+		t.Errorf("Expected two empty maps to share nothing out of 1 node, got %d of %d", s, n)
+	}
+}
@@ -0,0 +1,103 @@
+package immut
+
+import "sync/atomic"
+
+// An ImmutableSlice is a copy-on-write wrapper over a Go slice. Append and Set return new
+// wrappers, sharing the backing array until a write would clobber something another wrapper can
+// see. It suits mostly-append workloads where a trie backed Vector is overkill.
+//
+// Appending to the newest wrapper reuses spare capacity, so a run of appends is amortized O(1).
+// Appending to an older wrapper, or calling Set, copies the slice.
+type ImmutableSlice[T any] struct {
+	s     []T
+	owner *sliceOwner
+}
+
+// sliceOwner tracks how far the shared backing array has been claimed, so a wrapper only
+// appends in place when nobody has used the capacity past its end yet. Claims go through a
+// compare and swap so concurrent appends to the same wrapper never share a slot.
+type sliceOwner struct {
+	used atomic.Int64
+}
+
+// newSliceOwner returns an owner with the first used slots of the backing array claimed
+func newSliceOwner(used int) *sliceOwner {
+	o := &sliceOwner{}
+	o.used.Store(int64(used))
+	return o
+}
+
+// NewImmutableSlice returns a wrapper holding a copy of vals
+func NewImmutableSlice[T any](vals ...T) ImmutableSlice[T] {
+	s := make([]T, len(vals))
+	copy(s, vals)
+	return ImmutableSlice[T]{
+		s:     s,
+		owner: newSliceOwner(len(s)),
+	}
+}
+
+// Len returns the number of elements
+func (i ImmutableSlice[T]) Len() int {
+	return len(i.s)
+}
+
+// Get returns the element at the given index
+func (i ImmutableSlice[T]) Get(index int) (T, bool) {
+	if index < 0 || index >= len(i.s) {
+		var zero T
+		return zero, false
+	}
+
+	return i.s[index], true
+}
+
+// Append returns a new wrapper with vals added to the end
+func (i ImmutableSlice[T]) Append(vals ...T) ImmutableSlice[T] {
+	if i.owner != nil && cap(i.s)-len(i.s) >= len(vals) &&
+		i.owner.used.CompareAndSwap(int64(len(i.s)), int64(len(i.s)+len(vals))) {
+		s := append(i.s, vals...)
+		return ImmutableSlice[T]{
+			s:     s,
+			owner: i.owner,
+		}
+	}
+
+	s := make([]T, len(i.s), (len(i.s)+len(vals))*2)
+	copy(s, i.s)
+	s = append(s, vals...)
+	return ImmutableSlice[T]{
+		s:     s,
+		owner: newSliceOwner(len(s)),
+	}
+}
+
+// Set returns a new wrapper with the element at index replaced by val. The index must be in
+// range.
+func (i ImmutableSlice[T]) Set(index int, val T) ImmutableSlice[T] {
+	n := NewImmutableSlice(i.s...)
+	n.s[index] = val
+	return n
+}
+
+// SubSlice returns a wrapper over the [start, end) range, sharing the backing array. Appending
+// to it copies, since the elements past end still belong to i.
+func (i ImmutableSlice[T]) SubSlice(start, end int) ImmutableSlice[T] {
+	return ImmutableSlice[T]{
+		s: i.s[start:end:end],
+	}
+}
+
+// Each runs f on every index, element pair in order
+func (i ImmutableSlice[T]) Each(f func(index int, val T)) {
+	for x, v := range i.s {
+		f(x, v)
+	}
+}
+
+// ToSlice returns a copy of the elements as a plain slice
+func (i ImmutableSlice[T]) ToSlice() []T {
+	s := make([]T, len(i.s))
+	copy(s, i.s)
+	return s
+}
@@ -0,0 +1,94 @@
+package immut
+
+import "testing"
+
+func TestImmutableSliceAppend(t *testing.T) {
+	a := NewImmutableSlice(1, 2, 3)
+	b := a.Append(4)
+	c := a.Append(5)
+
+	if a.Len() != 3 || b.Len() != 4 || c.Len() != 4 {
+		t.Fatalf("Expected 3, 4, 4 got %d, %d, %d", a.Len(), b.Len(), c.Len())
+	}
+
+	if x, _ := b.Get(3); x != 4 {
+		t.Errorf("Appending to a sibling clobbered b, expected 4 got %d", x)
+	}
+
+	if x, _ := c.Get(3); x != 5 {
+		t.Errorf("Expected 5 got %d", x)
+	}
+}
+
+func TestImmutableSliceAppendInPlace(t *testing.T) {
+	a := NewImmutableSlice(1).Append(2)
+	b := a.Append(3)
+	if &a.s[0] != &b.s[0] {
+		t.Error("Appending to the newest wrapper should reuse its spare capacity")
+	}
+
+	c := a.Append(4)
+	if &a.s[0] == &c.s[0] {
+		t.Error("Appending to an older wrapper should copy")
+	}
+
+	if x, _ := b.Get(2); x != 3 {
+		t.Errorf("Expected 3 got %d", x)
+	}
+}
+
+func TestImmutableSliceConcurrentAppend(t *testing.T) {
+	a := NewImmutableSlice(0).Append(1)
+	out := make(chan ImmutableSlice[int])
+	for i := 0; i < 10; i++ {
+		go func(i int) {
+			out <- a.Append(100 + i)
+		}(i)
+	}
+
+	for i := 0; i < 10; i++ {
+		s := <-out
+		x, _ := s.Get(2)
+		for j := 0; j < 10; j++ {
+			y, _ := s.Get(2)
+			if x != y {
+				t.Fatal("Appended element changed under a reader")
+			}
+		}
+	}
+}
+
+func TestImmutableSliceSet(t *testing.T) {
+	a := NewImmutableSlice("a", "b")
+	b := a.Set(0, "z")
+
+	if x, _ := a.Get(0); x != "a" {
+		t.Errorf("Set should not modify the original, got %s", x)
+	}
+
+	if x, _ := b.Get(0); x != "z" {
+		t.Errorf("Expected z got %s", x)
+	}
+
+	if _, ok := b.Get(2); ok {
+		t.Error("Get out of range should fail")
+	}
+}
+
+func TestImmutableSliceSubSlice(t *testing.T) {
+	a := NewImmutableSlice(0, 1, 2, 3, 4).Append(5)
+	sub := a.SubSlice(1, 3)
+
+	if sub.Len() != 2 {
+		t.Fatalf("Expected 2 got %d", sub.Len())
+	}
+
+	sub = sub.Append(99)
+	if x, _ := a.Get(3); x != 3 {
+		t.Errorf("Appending to a sub slice clobbered the parent, got %d", x)
+	}
+
+	if out := sub.ToSlice(); len(out) != 3 || out[2] != 99 {
+		t.Errorf("Expected [1 2 99] got %v", out)
+	}
+}
@@ -0,0 +1,128 @@
+package immut
+
+import "sort"
+
+// Sort returns a stably sorted copy of the list. The longest already sorted run at the end of
+// the list is merged in without being copied, so nearly sorted lists share most of their nodes.
+func (l *List) Sort(less func(a, b interface{}) bool) *List {
+
+	// find where the sorted tail of the list starts
+	var prefix []interface{}
+	tail := l
+	for y := l; y != nil; y = y.next {
+		if !y.End() && less(y.next.val, y.val) {
+			prefix = append(prefix, tail.valuesUntil(y.next)...)
+			tail = y.next
+		}
+	}
+
+	if len(prefix) == 0 {
+		return l
+	}
+
+	sort.SliceStable(prefix, func(i, j int) bool {
+		return less(prefix[i], prefix[j])
+	})
+
+	// merge the sorted prefix with the tail, prefix first on ties to stay stable
+	merged := make([]interface{}, 0, len(prefix))
+	i := 0
+	for i < len(prefix) && tail != nil {
+		if less(tail.val, prefix[i]) {
+			merged = append(merged, tail.val)
+			tail = tail.next
+		} else {
+			merged = append(merged, prefix[i])
+			i++
+		}
+	}
+	merged = append(merged, prefix[i:]...)
+
+	n := tail
+	for j := len(merged) - 1; j >= 0; j-- {
+		n = n.Prepend(merged[j])
+	}
+
+	return n
+}
+
+// valuesUntil returns the values in the list up to but not including the node end
+func (l *List) valuesUntil(end *List) []interface{} {
+	var vals []interface{}
+	for y := l; y != nil && y != end; y = y.next {
+		vals = append(vals, y.val)
+	}
+
+	return vals
+}
+
+// IsSorted returns true if the list is sorted according to less
+func (l *List) IsSorted(less func(a, b interface{}) bool) bool {
+	for y := l; !y.End(); y = y.next {
+		if less(y.next.val, y.val) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// values returns the values stored at indexes [0, Size) in order, with nil for any hole. It
+// places every stored entry at its own index rather than looking each index up.
+func (v *Vector) values() []interface{} {
+	vals := make([]interface{}, v.Size())
+	v.root.eachEntry(func(e Entry) {
+		vals[int(e.hashedKey-uint32(v.offset))] = e.value
+	})
+
+	return vals
+}
+
+// Sort returns a stably sorted copy of the vector. A vector that is already sorted is returned
+// as is.
+func (v *Vector) Sort(less func(a, b interface{}) bool) *Vector {
+	if v.IsSorted(less) {
+		return v
+	}
+
+	vals := v.values()
+	sort.SliceStable(vals, func(i, j int) bool {
+		return less(vals[i], vals[j])
+	})
+
+	n := NewVector()
+	for i, x := range vals {
+		n = n.Put(i, x)
+	}
+
+	return n
+}
+
+// IsSorted returns true if the vector is sorted according to less
+func (v *Vector) IsSorted(less func(a, b interface{}) bool) bool {
+	vals := v.values()
+	for i := 1; i < len(vals); i++ {
+		if less(vals[i], vals[i-1]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// BinarySearch looks for x in a vector sorted by less. It returns the index of x if found, or
+// the index x would have to be inserted at to keep the vector sorted.
+func (v *Vector) BinarySearch(x interface{}, less func(a, b interface{}) bool) (int, bool) {
+	i := sort.Search(v.Size(), func(i int) bool {
+		y, _ := v.Get(i)
+		return !less(y, x)
+	})
+
+	if i < v.Size() {
+		if y, _ := v.Get(i); !less(x, y) {
+			return i, true
+		}
+	}
+
+	return i, false
+}
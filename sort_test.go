@@ -0,0 +1,130 @@
+package immut
+
+import (
+	"math/rand"
+	"testing"
+)
+
+type sortPair struct {
+	key, order int
+}
+
+func pairLess(a, b interface{}) bool {
+	return a.(sortPair).key < b.(sortPair).key
+}
+
+func TestListSort(t *testing.T) {
+	vals := make([]interface{}, 200)
+	for i := range vals {
+		vals[i] = sortPair{key: rand.Intn(20), order: i}
+	}
+
+	l := ListFromSlice(vals)
+	s := l.Sort(pairLess)
+
+	if !s.IsSorted(pairLess) {
+		t.Fatalf("List not sorted: %s", s)
+	}
+
+	if s.Len() != l.Len() {
+		t.Errorf("Expected %d got %d", l.Len(), s.Len())
+	}
+
+	// equal keys keep their original order
+	last := sortPair{key: -1}
+	s.Each(func(i interface{}) {
+		p := i.(sortPair)
+		if p.key == last.key && p.order < last.order {
+			t.Fatalf("Sort not stable: %v after %v", p, last)
+		}
+		last = p
+	})
+
+	if l.IsSorted(pairLess) && l.Len() > 1 {
+		t.Error("Sort should not modify the original list")
+	}
+}
+
+func TestListSortShares(t *testing.T) {
+	sorted := ListFromSlice([]interface{}{1, 2, 3})
+	if sorted.Sort(intLess) != sorted {
+		t.Error("Sorting a sorted list should return it as is")
+	}
+
+	tail := ListFromSlice([]interface{}{4, 5, 6, 7})
+	l := tail.Prepend(2).Prepend(9)
+	s := l.Sort(intLess)
+
+	if s.String() != "[2, 4, 5, 6, 7, 9]" {
+		t.Fatalf("Expected [2, 4, 5, 6, 7, 9] got %s", s)
+	}
+
+	// 9 is larger than the whole tail so only the 9 node is new after it
+	y := s
+	for y.Val() != 9 {
+		y = y.Next()
+	}
+
+	if y.Next() != nil {
+		t.Error("9 should be last")
+	}
+
+	var e *List
+	if e.Sort(intLess) != nil {
+		t.Error("Sorting the empty list should give the empty list")
+	}
+}
+
+func TestVectorSort(t *testing.T) {
+	v := NewVector()
+	for i := 0; i < 100; i++ {
+		v = v.Put(i, rand.Intn(50))
+	}
+
+	s := v.Sort(intLess)
+	if !s.IsSorted(intLess) {
+		t.Fatal("Vector not sorted")
+	}
+
+	if s.Sort(intLess) != s {
+		t.Error("Sorting a sorted vector should return it as is")
+	}
+
+	for i := 0; i < 50; i++ {
+		idx, found := s.BinarySearch(i, intLess)
+		if found {
+			if x, _ := s.Get(idx); x != i {
+				t.Errorf("Expected %d at %d got %v", i, idx, x)
+			}
+		}
+
+		if idx > 0 {
+			if x, _ := s.Get(idx - 1); !intLess(x, i) {
+				t.Errorf("%v before the insert point should be less than %d", x, i)
+			}
+		}
+	}
+
+	if idx, found := s.BinarySearch(100, intLess); found || idx != s.Size() {
+		t.Errorf("Expected %d, false got %d, %t", s.Size(), idx, found)
+	}
+}
+
+func TestVectorValues(t *testing.T) {
+	v := NewVector()
+	for i := 0; i < 100; i++ {
+		v = v.Put(i, i)
+	}
+	v, _ = v.SliceRange(10, 90)
+
+	vals := v.values()
+	if len(vals) != 80 {
+		t.Fatalf("Expected 80 values got %d", len(vals))
+	}
+
+	for i, x := range vals {
+		if x != i+10 {
+			t.Fatalf("Expected %d at %d got %v", i+10, i, x)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package immut
+
+import "sort"
+
+// A Pair is a single k,v pair taken out of a map
+type Pair struct {
+	Key interface{}
+	Val interface{}
+}
+
+// Entries returns all of the k,v pairs stored in the hash map
+func (h *HashMap) Entries() []Pair {
+	entries := make([]Pair, 0, h.Len())
+	h.Each(func(k, v interface{}) {
+		entries = append(entries, Pair{Key: k, Val: v})
+	})
+
+	return entries
+}
+
+// KeysSorted returns the keys stored in the hash map, sorted by less
+func (h *HashMap) KeysSorted(less func(a, b interface{}) bool) []interface{} {
+	keys := h.Keys()
+	sort.Slice(keys, func(i, j int) bool {
+		return less(keys[i], keys[j])
+	})
+
+	return keys
+}
+
+// EntriesSorted returns the k,v pairs stored in the hash map, sorted by less on their keys
+func (h *HashMap) EntriesSorted(less func(a, b interface{}) bool) []Pair {
+	entries := h.Entries()
+	sort.Slice(entries, func(i, j int) bool {
+		return less(entries[i].Key, entries[j].Key)
+	})
+
+	return entries
+}
+
+// EachSorted runs f on each k,v pair in the order of less on their keys
+func (h *HashMap) EachSorted(less func(a, b interface{}) bool, f func(k, v interface{})) {
+	for _, e := range h.EntriesSorted(less) {
+		f(e.Key, e.Val)
+	}
+}
@@ -0,0 +1,49 @@
+package immut
+
+import (
+	"sort"
+	"testing"
+)
+
+func intLess(a, b interface{}) bool {
+	return a.(int) < b.(int)
+}
+
+func TestKeysSorted(t *testing.T) {
+	h := NewHashMap()
+	for _, i := range []int{5, 3, 9, 1, 7} {
+		h = h.Put(i, i*10)
+	}
+
+	keys := h.KeysSorted(intLess)
+	if !sort.SliceIsSorted(keys, func(i, j int) bool { return intLess(keys[i], keys[j]) }) {
+		t.Errorf("Keys not sorted: %v", keys)
+	}
+
+	if len(keys) != 5 {
+		t.Errorf("Expected 5 got %d", len(keys))
+	}
+}
+
+func TestEachSorted(t *testing.T) {
+	h := NewHashMap()
+	for i := 100; i > 0; i-- {
+		h = h.Put(i, i*10)
+	}
+
+	last := 0
+	h.EachSorted(intLess, func(k, v interface{}) {
+		if k.(int) <= last {
+			t.Fatalf("%d came after %d", k, last)
+		}
+
+		if v != k.(int)*10 {
+			t.Errorf("Expected %d got %v", k.(int)*10, v)
+		}
+		last = k.(int)
+	})
+
+	if last != 100 {
+		t.Errorf("Expected to end at 100 got %d", last)
+	}
+}
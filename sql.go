@@ -0,0 +1,116 @@
+package immut
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// SQLEncoding picks how a HashMap or Vector is stored in a database column
+type SQLEncoding int
+
+const (
+	// SQLJSON stores the value as JSON, for JSON/JSONB or text columns
+	SQLJSON SQLEncoding = iota
+
+	// SQLGob stores the value in its gob wire format, for BLOB/BYTEA columns. Concrete types
+	// stored in it must be registered with RegisterGob.
+	SQLGob
+)
+
+// An SQLColumn wraps a *HashMap or *Vector so it can be passed to database/sql as a query
+// argument or a Scan destination
+type SQLColumn struct {
+	x   interface{}
+	enc SQLEncoding
+}
+
+// SQL wraps a *HashMap or *Vector to be stored with the given encoding
+func SQL(x interface{}, enc SQLEncoding) *SQLColumn {
+	return &SQLColumn{
+		x:   x,
+		enc: enc,
+	}
+}
+
+// Value implements driver.Valuer
+func (c *SQLColumn) Value() (driver.Value, error) {
+	switch x := c.x.(type) {
+	case *HashMap:
+		if c.enc == SQLJSON {
+			return x.MarshalJSON()
+		}
+		return x.GobEncode()
+	case *Vector:
+		if c.enc == SQLJSON {
+			return x.MarshalJSON()
+		}
+		return x.GobEncode()
+	}
+
+	return nil, fmt.Errorf("immut: can't store %T in a database column", c.x)
+}
+
+// Scan implements sql.Scanner, replacing the wrapped *HashMap or *Vector with the column's
+// contents. JSON map keys come back as strings.
+func (c *SQLColumn) Scan(src interface{}) error {
+	var data []byte
+	switch src := src.(type) {
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	case nil:
+		return c.reset()
+	default:
+		return fmt.Errorf("immut: can't scan %T into %T", src, c.x)
+	}
+
+	switch x := c.x.(type) {
+	case *HashMap:
+		if c.enc == SQLJSON {
+			return x.UnmarshalJSON(data)
+		}
+		return x.GobDecode(data)
+	case *Vector:
+		if c.enc == SQLJSON {
+			return x.UnmarshalJSON(data)
+		}
+		return x.GobDecode(data)
+	}
+
+	return fmt.Errorf("immut: can't scan into %T", c.x)
+}
+
+// reset empties the wrapped value for a NULL column
+func (c *SQLColumn) reset() error {
+	switch x := c.x.(type) {
+	case *HashMap:
+		*x = *NewHashMap()
+	case *Vector:
+		*x = *NewVector()
+	default:
+		return fmt.Errorf("immut: can't scan into %T", c.x)
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer by storing the map as JSON
+func (h *HashMap) Value() (driver.Value, error) {
+	return SQL(h, SQLJSON).Value()
+}
+
+// Scan implements sql.Scanner by reading the map from JSON
+func (h *HashMap) Scan(src interface{}) error {
+	return SQL(h, SQLJSON).Scan(src)
+}
+
+// Value implements driver.Valuer by storing the vector as a JSON array
+func (v *Vector) Value() (driver.Value, error) {
+	return SQL(v, SQLJSON).Value()
+}
+
+// Scan implements sql.Scanner by reading the vector from a JSON array
+func (v *Vector) Scan(src interface{}) error {
+	return SQL(v, SQLJSON).Scan(src)
+}
@@ -0,0 +1,83 @@
+package immut
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = &HashMap{}
+	_ sql.Scanner   = &HashMap{}
+	_ driver.Valuer = &Vector{}
+	_ sql.Scanner   = &Vector{}
+	_ driver.Valuer = &SQLColumn{}
+	_ sql.Scanner   = &SQLColumn{}
+)
+
+func TestHashMapSQL(t *testing.T) {
+	for _, enc := range []SQLEncoding{SQLJSON, SQLGob} {
+		h := NewHashMap().Put("a", "x").Put("b", "y")
+
+		v, err := SQL(h, enc).Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := NewHashMap()
+		if err := SQL(out, enc).Scan(v); err != nil {
+			t.Fatal(err)
+		}
+
+		if out.Len() != 2 {
+			t.Errorf("Expected 2 got %d", out.Len())
+		}
+
+		if x, _ := out.Get("b"); x != "y" {
+			t.Errorf("Expected y got %v", x)
+		}
+	}
+}
+
+func TestVectorSQL(t *testing.T) {
+	for _, enc := range []SQLEncoding{SQLJSON, SQLGob} {
+		v := NewVector().Put(0, "a").Put(1, "b").Put(2, "c")
+
+		val, err := SQL(v, enc).Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := NewVector()
+		if err := SQL(out, enc).Scan(val); err != nil {
+			t.Fatal(err)
+		}
+
+		if out.Size() != 3 {
+			t.Errorf("Expected 3 got %d", out.Size())
+		}
+
+		if x, _ := out.Get(2); x != "c" {
+			t.Errorf("Expected c got %v", x)
+		}
+	}
+}
+
+func TestSQLScanString(t *testing.T) {
+	h := NewHashMap()
+	if err := h.Scan(`{"a": "b"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	if x, _ := h.Get("a"); x != "b" {
+		t.Errorf("Expected b got %v", x)
+	}
+
+	if err := h.Scan(nil); err != nil || h.Len() != 0 {
+		t.Errorf("Scanning NULL should give an empty map, got %d, %v", h.Len(), err)
+	}
+
+	if err := h.Scan(12); err == nil {
+		t.Error("Expected an error scanning an int")
+	}
+}
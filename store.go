@@ -0,0 +1,166 @@
+package immut
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+)
+
+// A Store holds the current version of a HashMap and tells subscribers what changed every time
+// it is replaced. Reads never block, updates are applied one at a time.
+type Store struct {
+	cur atomic.Pointer[HashMap]
+
+	// mu serializes updates. Subscribers are called without it, one update at a time from
+	// pending, so every subscriber sees the changes in the order they were made.
+	mu        sync.Mutex
+	subs      map[int]*storeSub
+	next      int
+	pending   []storeUpdate
+	notifying bool
+}
+
+// storeSub is a single subscription to a store
+type storeSub struct {
+	match     func(Change) bool
+	f         func(Change)
+	cancelled atomic.Bool
+}
+
+// storeUpdate is a swap waiting to be sent to the subscribers the store had when it was made
+type storeUpdate struct {
+	old, n *HashMap
+	subs   []*storeSub
+}
+
+// NewStore returns a store holding h
+func NewStore(h *HashMap) *Store {
+	s := &Store{
+		subs: make(map[int]*storeSub),
+	}
+	s.cur.Store(h)
+
+	return s
+}
+
+// Get returns the current version of the map
+func (s *Store) Get() *HashMap {
+	return s.cur.Load()
+}
+
+// Swap replaces the map with the result of running f on the current version, then notifies the
+// subscribers of every change between the two versions. It returns the new version.
+//
+// Subscribers are called without holding the store's lock, so they may call Swap, Set or a
+// cancel function. They are called on the goroutine running Swap before it returns, unless
+// another Swap is already notifying, in which case that goroutine delivers this update too once
+// it is done with the ones before it.
+func (s *Store) Swap(f func(*HashMap) *HashMap) *HashMap {
+	s.mu.Lock()
+	old := s.cur.Load()
+	n := f(old)
+	s.cur.Store(n)
+
+	if len(s.subs) == 0 {
+		s.mu.Unlock()
+		return n
+	}
+
+	subs := make([]*storeSub, 0, len(s.subs))
+	for _, sub := range s.subs {
+		subs = append(subs, sub)
+	}
+	s.pending = append(s.pending, storeUpdate{old: old, n: n, subs: subs})
+
+	if s.notifying {
+		s.mu.Unlock()
+		return n
+	}
+	s.notifying = true
+	s.mu.Unlock()
+
+	s.notify()
+	return n
+}
+
+// notify sends pending updates to their subscribers until there are none left. Only one
+// goroutine runs it at a time.
+func (s *Store) notify() {
+	done := false
+	defer func() {
+		// a subscriber panicked, let the next Swap start over
+		if !done {
+			s.mu.Lock()
+			s.pending = nil
+			s.notifying = false
+			s.mu.Unlock()
+		}
+	}()
+
+	for {
+		s.mu.Lock()
+		if len(s.pending) == 0 {
+			s.notifying = false
+			s.mu.Unlock()
+			done = true
+			return
+		}
+		u := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+
+		for _, c := range u.old.Diff(u.n) {
+			for _, sub := range u.subs {
+				if !sub.cancelled.Load() && sub.match(c) {
+					sub.f(c)
+				}
+			}
+		}
+	}
+}
+
+// Set replaces the map with h and notifies the subscribers like Swap
+func (s *Store) Set(h *HashMap) {
+	s.Swap(func(*HashMap) *HashMap {
+		return h
+	})
+}
+
+// Subscribe calls f with every change made to the store until the returned cancel function is
+// called
+func (s *Store) Subscribe(f func(Change)) (cancel func()) {
+	return s.SubscribeWhere(func(Change) bool {
+		return true
+	}, f)
+}
+
+// SubscribeKey calls f with every change made to k until the returned cancel function is called
+func (s *Store) SubscribeKey(k interface{}, f func(Change)) (cancel func()) {
+	b := iToBytes(k)
+	return s.SubscribeWhere(func(c Change) bool {
+		return bytes.Equal(iToBytes(c.Key), b)
+	}, f)
+}
+
+// SubscribeWhere calls f with every change that match returns true for until the returned
+// cancel function is called
+func (s *Store) SubscribeWhere(match func(Change) bool, f func(Change)) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.next
+	s.next++
+	sub := &storeSub{
+		match: match,
+		f:     f,
+	}
+	s.subs[id] = sub
+
+	return func() {
+		sub.cancelled.Store(true)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subs, id)
+	}
+}
@@ -0,0 +1,137 @@
+package immut
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestStoreSwap(t *testing.T) {
+	s := NewStore(NewHashMap().Put("a", 1).Put("b", 2))
+
+	var all, onA []Change
+	s.Subscribe(func(c Change) {
+		all = append(all, c)
+	})
+	s.SubscribeKey("a", func(c Change) {
+		onA = append(onA, c)
+	})
+
+	n := s.Swap(func(h *HashMap) *HashMap {
+		h, _ = h.Del("b")
+		return h.Put("a", 10).Put("c", 3)
+	})
+
+	if s.Get() != n {
+		t.Error("Expected Swap to return the current version")
+	}
+
+	if len(all) != 3 {
+		t.Errorf("Expected 3 changes got %v", all)
+	}
+
+	if len(onA) != 1 || onA[0].Kind != Modified || onA[0].Old != 1 || onA[0].New != 10 {
+		t.Errorf("Expected a to be modified from 1 to 10, got %v", onA)
+	}
+
+	s.Swap(func(h *HashMap) *HashMap {
+		return h
+	})
+
+	if len(all) != 3 {
+		t.Errorf("Expected no changes for an unchanged version, got %v", all[3:])
+	}
+}
+
+func TestStoreCancel(t *testing.T) {
+	s := NewStore(NewHashMap())
+
+	count := 0
+	cancel := s.Subscribe(func(Change) {
+		count++
+	})
+
+	s.Set(s.Get().Put("a", 1))
+	cancel()
+	s.Set(s.Get().Put("b", 2))
+
+	if count != 1 {
+		t.Errorf("Expected 1 change before cancel got %d", count)
+	}
+}
+
+func TestStoreSubscribeWhere(t *testing.T) {
+	s := NewStore(NewHashMap().Put("a", 1))
+
+	var added []Change
+	s.SubscribeWhere(func(c Change) bool {
+		return c.Kind == Added
+	}, func(c Change) {
+		added = append(added, c)
+	})
+
+	s.Set(s.Get().Put("e", 5).Put("a", 0))
+	if len(added) != 1 || added[0].Key != "e" {
+		t.Errorf("Expected only e to be reported, got %v", added)
+	}
+}
+
+func TestStoreConcurrent(t *testing.T) {
+	s := NewStore(NewHashMap())
+
+	var mu sync.Mutex
+	seen := 0
+	s.Subscribe(func(Change) {
+		mu.Lock()
+		seen++
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				s.Swap(func(h *HashMap) *HashMap {
+					return h.Put(i*50+j, j)
+				})
+				s.Get().Get(i * 50)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if s.Get().Len() != 400 || seen != 400 {
+		t.Errorf("Expected 400 keys and changes got %d and %d", s.Get().Len(), seen)
+	}
+}
+
+func TestStoreCallbackReenters(t *testing.T) {
+	s := NewStore(NewHashMap())
+
+	var keys []interface{}
+	var cancel func()
+	cancel = s.Subscribe(func(c Change) {
+		keys = append(keys, c.Key)
+		if c.Key == "a" {
+			s.Set(s.Get().Put("b", 2))
+			cancel()
+		}
+	})
+
+	count := 0
+	s.Subscribe(func(Change) {
+		count++
+	})
+
+	s.Set(s.Get().Put("a", 1))
+	s.Set(s.Get().Put("c", 3))
+
+	if len(keys) != 1 || keys[0] != "a" {
+		t.Errorf("Expected only a before cancelling got %v", keys)
+	}
+
+	if count != 3 || s.Get().Len() != 3 {
+		t.Errorf("Expected 3 changes and keys got %d and %d", count, s.Get().Len())
+	}
+}
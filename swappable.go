@@ -0,0 +1,50 @@
+package immut
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// A Swappable holds a value that is read far more often than it is replaced, like a routing
+// table or config snapshot built from immut structures. Load is a single atomic read and never
+// blocks or allocates. Replacements are serialized, so each update function sees the result of
+// the last one. The zero value holds the zero value of T.
+type Swappable[T any] struct {
+	p  atomic.Pointer[T]
+	mu sync.Mutex
+}
+
+// NewSwappable returns a Swappable holding v
+func NewSwappable[T any](v T) *Swappable[T] {
+	s := &Swappable[T]{}
+	s.p.Store(&v)
+	return s
+}
+
+// Load returns the current value
+func (s *Swappable[T]) Load() T {
+	if p := s.p.Load(); p != nil {
+		return *p
+	}
+
+	var zero T
+	return zero
+}
+
+// Store replaces the current value with v
+func (s *Swappable[T]) Store(v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.p.Store(&v)
+}
+
+// Replace replaces the current value with the result of f on it, and returns the new value. f
+// is called exactly once, while other replacements wait, so it should be quick.
+func (s *Swappable[T]) Replace(f func(T) T) T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := f(s.Load())
+	s.p.Store(&v)
+	return v
+}
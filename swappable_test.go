@@ -0,0 +1,92 @@
+package immut
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestSwappable(t *testing.T) {
+	var zero Swappable[*HashMap]
+	if zero.Load() != nil {
+		t.Error("Expected the zero value to hold nil")
+	}
+
+	s := NewSwappable(NewHashMap())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Replace(func(h *HashMap) *HashMap {
+					return h.Put(i*100+j, j)
+				})
+				s.Load().Get(i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if s.Load().Len() != 800 {
+		t.Errorf("Expected 800 got %d", s.Load().Len())
+	}
+
+	s.Store(NewHashMap())
+	if s.Load().Len() != 0 {
+		t.Error("Expected Store to replace the value")
+	}
+}
+
+func BenchmarkSwappableLoad(b *testing.B) {
+	s := NewSwappable(NewHashMap().Put("a", 1))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Load().Get("a")
+		}
+	})
+}
+
+// A routing table that can be reloaded while requests are being served
+func ExampleSwappable() {
+	routes := NewSwappable(NewHashMap().
+		Put("/hello", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "hello")
+		})))
+
+	router := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, found := routes.Load().Get(r.URL.Path)
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		h.(http.Handler).ServeHTTP(w, r)
+	})
+
+	serve := func(path string) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		fmt.Println(path, w.Code)
+	}
+
+	serve("/hello")
+	serve("/bye")
+
+	// hot reload a new route without stopping the server
+	routes.Replace(func(h *HashMap) *HashMap {
+		return h.Put("/bye", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "bye")
+		}))
+	})
+
+	serve("/bye")
+
+	// Output:
+	// /hello 200
+	// /bye 404
+	// /bye 200
+}
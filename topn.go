@@ -0,0 +1,73 @@
+package immut
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// MaxBy returns the greatest k,v pair in the map according to less
+func (h *HashMap) MaxBy(less func(a, b Pair) bool) (Pair, bool) {
+	var max Pair
+	found := false
+	h.Each(func(k, v interface{}) {
+		p := Pair{Key: k, Val: v}
+		if !found || less(max, p) {
+			max = p
+			found = true
+		}
+	})
+
+	return max, found
+}
+
+// MinBy returns the smallest k,v pair in the map according to less
+func (h *HashMap) MinBy(less func(a, b Pair) bool) (Pair, bool) {
+	return h.MaxBy(func(a, b Pair) bool {
+		return less(b, a)
+	})
+}
+
+// TopN returns the n greatest k,v pairs in the map according to less, greatest first.
+// It keeps a heap of at most n pairs so it runs in O(len * log n).
+func (h *HashMap) TopN(n int, less func(a, b Pair) bool) []Pair {
+	if n <= 0 {
+		return nil
+	}
+
+	p := &pairHeap{less: less}
+	h.Each(func(k, v interface{}) {
+		x := Pair{Key: k, Val: v}
+		if len(p.pairs) < n {
+			heap.Push(p, x)
+		} else if less(p.pairs[0], x) {
+			p.pairs[0] = x
+			heap.Fix(p, 0)
+		}
+	})
+
+	sort.Slice(p.pairs, func(i, j int) bool {
+		return less(p.pairs[j], p.pairs[i])
+	})
+
+	return p.pairs
+}
+
+// pairHeap is a min heap of pairs ordered by less
+type pairHeap struct {
+	pairs []Pair
+	less  func(a, b Pair) bool
+}
+
+func (p *pairHeap) Len() int           { return len(p.pairs) }
+func (p *pairHeap) Less(i, j int) bool { return p.less(p.pairs[i], p.pairs[j]) }
+func (p *pairHeap) Swap(i, j int)      { p.pairs[i], p.pairs[j] = p.pairs[j], p.pairs[i] }
+
+func (p *pairHeap) Push(x interface{}) {
+	p.pairs = append(p.pairs, x.(Pair))
+}
+
+func (p *pairHeap) Pop() interface{} {
+	x := p.pairs[len(p.pairs)-1]
+	p.pairs = p.pairs[:len(p.pairs)-1]
+	return x
+}
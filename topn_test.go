@@ -0,0 +1,67 @@
+package immut
+
+import "testing"
+
+func valLess(a, b Pair) bool {
+	return a.Val.(int) < b.Val.(int)
+}
+
+func TestMaxMinBy(t *testing.T) {
+	h := NewHashMap()
+	if _, found := h.MaxBy(valLess); found {
+		t.Error("Empty map should not have a max")
+	}
+
+	for i := 0; i < 100; i++ {
+		h = h.Put(i, (i*37)%101)
+	}
+
+	max, _ := h.MaxBy(valLess)
+	if max.Val != 100 {
+		t.Errorf("Expected 100 got %v", max.Val)
+	}
+
+	min, _ := h.MinBy(valLess)
+	if min.Val != 0 || min.Key != 0 {
+		t.Errorf("Expected 0:0 got %v:%v", min.Key, min.Val)
+	}
+}
+
+func TestTopN(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 1000; i++ {
+		h = h.Put(i, i)
+	}
+
+	top := h.TopN(5, valLess)
+	if len(top) != 5 {
+		t.Fatalf("Expected 5 got %d", len(top))
+	}
+
+	for i, p := range top {
+		if p.Val != 999-i {
+			t.Errorf("Expected %d got %v", 999-i, p.Val)
+		}
+	}
+
+	if all := h.TopN(2000, valLess); len(all) != 1000 {
+		t.Errorf("Expected 1000 got %d", len(all))
+	}
+
+	if none := h.TopN(0, valLess); len(none) != 0 {
+		t.Errorf("Expected 0 got %d", len(none))
+	}
+}
+
+func BenchmarkTopN(b *testing.B) {
+	h := NewHashMap()
+	for i := 0; i < 10000; i++ {
+		h = h.Put(i, i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.TopN(10, valLess)
+	}
+}
@@ -4,13 +4,27 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"sort"
 )
 
 const (
 	bits     = 4
 	width    = 1 << bits
 	mask     = width - 1
-	maxDepth = 16 / bits
+	maxDepth = 32 / bits
+
+	// smallSize is the most entries a trie keeps inline in its root before growing children
+	smallSize = 8
+)
+
+// The shape of every trie, exported so tests outside the package can build keys that reach a
+// given depth. Each level is indexed by the next TrieBits bits of a key's 32 bit hash, so a
+// node has TrieWidth children. Below TrieMaxDepth levels the whole hash has been used up and the
+// keys left share it completely, so they are kept in a linear bucket instead of more levels.
+const (
+	TrieBits     = bits
+	TrieWidth    = width
+	TrieMaxDepth = maxDepth
 )
 
 // A Trie is an immutible implementation of of trie
@@ -18,12 +32,15 @@ const (
 // Read about it at http://hypirion.com/musings/understanding-persistent-vector-pt-2
 type Trie struct {
 	root *TNode
-	size int
+
+	// hasher hashes the keys, nil means hashKey
+	hasher Hasher
 }
 
-// Size returns the number of keys/vals in the trie
+// Size returns the number of keys/vals in the trie. Every node keeps count of the entries below
+// it, so this is O(1) even for tries assembled from other tries' subtrees.
 func (t *Trie) Size() int {
-	return t.size
+	return t.root.size
 }
 
 // NewTrie creates an empty Trie and returns it
@@ -35,27 +52,71 @@ func NewTrie() *Trie {
 
 // Put inserts the given value at the given key
 func (t *Trie) Put(key []byte, val interface{}) *Trie {
+	n, _ := t.root.put(t.entry(key, val))
+	return &Trie{
+		root:   n,
+		hasher: t.hasher,
+	}
+}
+
+// PutIfAbsent inserts the given value at the given key unless the key already exists. It returns
+// the value that ends up stored at the key and whether it was inserted.
+func (t *Trie) PutIfAbsent(key []byte, val interface{}) (*Trie, interface{}, bool) {
+	n, old, found := t.root.insert(t.entry(key, val), false)
+	if found {
+		return t, old.value, false
+	}
+
 	return &Trie{
-		root: t.root.Put(key, val),
-		size: t.size + 1,
+		root:   n,
+		hasher: t.hasher,
+	}, val, true
+}
+
+// getOrPut returns the value stored at the given key. If there is none it stores the result of f
+// there instead, in the same walk of the trie, and returns true along with the new trie.
+func (t *Trie) getOrPut(key []byte, f func() interface{}) (*Trie, interface{}, bool) {
+	var v interface{}
+	n, old, found := t.root.insert(t.entry(key, lazyValue(func() interface{} {
+		v = f()
+		return v
+	})), false)
+	if found {
+		return t, old.value, false
 	}
+
+	return &Trie{
+		root:   n,
+		hasher: t.hasher,
+	}, v, true
 }
 
 // Get returns the value stored at the given key
 func (t *Trie) Get(key []byte) (interface{}, bool) {
-	return t.root.Get(key)
+
+	// small tries are a short list of entries, no need to hash the key to find it
+	if t.root.leaf() {
+		for _, e := range t.root.vals {
+			if bytes.Equal(e.rawKey, key) {
+				return e.value, true
+			}
+		}
+		return nil, false
+	}
+
+	return t.root.get(t.entry(key, nil))
 }
 
 // Del remove the value stored at the given key and return the value that was stored there
 func (t *Trie) Del(key []byte) (*Trie, interface{}) {
-	n, i, b := t.root.Del(key)
+	n, i, b := t.root.del(t.entry(key, nil))
 	if !b {
 		return t, nil
 	}
 
 	return &Trie{
-		root: n,
-		size: t.size - 1,
+		root:   n,
+		hasher: t.hasher,
 	}, i
 }
 
@@ -66,8 +127,8 @@ func (t *Trie) Each(f func([]byte, interface{})) {
 
 // Keys returns all of the keys stored in the trie
 func (t *Trie) Keys() [][]byte {
-	keys := make([][]byte, t.size)
-	if t.size == 0 {
+	keys := make([][]byte, t.Size())
+	if t.Size() == 0 {
 		return keys
 	}
 	count := 0
@@ -81,7 +142,7 @@ func (t *Trie) Keys() [][]byte {
 
 // Values returns all fo the values stored in the trie
 func (t *Trie) Values() []interface{} {
-	values := make([]interface{}, t.size)
+	values := make([]interface{}, t.Size())
 	count := 0
 	t.Each(func(k []byte, v interface{}) {
 		values[count] = v
@@ -98,12 +159,25 @@ type Entry struct {
 	value     interface{}
 }
 
+// lazyValue is an entry value that is only worked out once insert stores the entry, so a value
+// that is expensive to build is skipped when the key turns out to be there already
+type lazyValue func() interface{}
+
+// resolve returns e with a lazy value replaced by its result
+func (t Entry) resolve() Entry {
+	if f, ok := t.value.(lazyValue); ok {
+		t.value = f()
+	}
+
+	return t
+}
+
 func printBits(u uint32) {
 	fmt.Printf("%0b \n", u)
 }
 
 func (t Entry) indexAtDepth(depth uint32) uint32 {
-	return (t.hashedKey >> (depth)) & mask
+	return (t.hashedKey >> (depth * bits)) & mask
 }
 
 func (t Entry) sameKey(check Entry) bool {
@@ -131,18 +205,23 @@ type TNode struct {
 	depth    uint32
 	vals     []Entry
 	children [width]*TNode
+
+	// size is the number of entries in the node and all of it's children
+	size int
 }
 
 // NewTNode creates and returns a new *TNode
 func NewTNode(parent *TNode, vals []Entry) *TNode {
 	t := TNode{
 		vals: vals,
+		size: len(vals),
 	}
 
 	if parent != nil {
 		t.depth = parent.depth + 1
 	}
 
+	observe(EventNodeAlloc, 1)
 	return &t
 }
 
@@ -161,6 +240,24 @@ func (t *TNode) Each(f func([]byte, interface{})) {
 	}
 }
 
+// eachUntil runs f on every entry in the node and it's children until f returns false. It
+// returns false if it was stopped early.
+func (t *TNode) eachUntil(f func(Entry) bool) bool {
+	for _, e := range t.vals {
+		if !f(e) {
+			return false
+		}
+	}
+
+	for i := 0; i < len(t.children); i++ {
+		if t.children[i] != nil && !t.children[i].eachUntil(f) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // String returns the string representation of the TNode
 func (t *TNode) String() string {
 	if t == nil {
@@ -185,25 +282,34 @@ func (t *TNode) Del(key []byte) (*TNode, interface{}, bool) {
 }
 
 func (t *TNode) del(e Entry) (*TNode, interface{}, bool) {
-	// find the index at the given depth, if it doesn't exist, try to go lower until it does
-	z := *t
-	y := &z
 
 	// hunt for the key at the current level's values
-	for i := 0; i < len(z.vals); i++ {
-		if z.vals[i].sameKey(e) {
-
-			// delete in slice
-			y.vals = append(y.vals[:i], y.vals[i:]...)
-			return y, z.vals[i].value, true
+	for i := 0; i < len(t.vals); i++ {
+		if t.vals[i].sameKey(e) {
+
+			// delete in a copy of the slice, the old one is still shared
+			vals := make([]Entry, 0, len(t.vals)-1)
+			vals = append(vals, t.vals[:i]...)
+			y := t.copy()
+			y.vals = append(vals, t.vals[i+1:]...)
+			y.size--
+			return y, t.vals[i].value, true
 		}
 	}
-	index := e.indexAtDepth(t.depth)
 
-	if y.children[index] != nil {
-		n, i, b := y.children[index].del(e)
+	// if it isn't here, try to go lower until it is
+	index := e.indexAtDepth(t.depth)
+	if t.children[index] != nil {
+		n, i, b := t.children[index].del(e)
 		if b {
+
+			// drop subtrees that no longer hold anything
+			if n.size == 0 {
+				n = nil
+			}
+			y := t.copy()
 			y.children[index] = n
+			y.size--
 			return y, i, b
 		}
 
@@ -215,52 +321,163 @@ func (t *TNode) del(e Entry) (*TNode, interface{}, bool) {
 // Put inserts a key, val pair into the TNode
 func (t *TNode) Put(key []byte, val interface{}) *TNode {
 	e := newEntry(key, val)
-	return t.put(e)
+	n, _ := t.put(e)
+	return n
 }
 
-func (t *TNode) put(e Entry) *TNode {
+func (t *TNode) put(e Entry) (*TNode, bool) {
+	n, _, replaced := t.insert(e, true)
+	return n, replaced
+}
+
+// insert adds e to the node and returns the entry already stored at its key if there was one.
+// An existing entry is only replaced when overwrite is set, otherwise t is returned untouched.
+//
+// Most tries are small, so a root without children keeps up to smallSize entries inline, sorted
+// by key, and only grows into a real trie once it outgrows that. This saves a node per entry and
+// lets Get on a small trie skip hashing.
+func (t *TNode) insert(e Entry, overwrite bool) (*TNode, Entry, bool) {
+	if t.depth > 0 || !t.leaf() {
+		return t.insertChild(e, overwrite)
+	}
+
+	i := sort.Search(len(t.vals), func(i int) bool {
+		return bytes.Compare(t.vals[i].rawKey, e.rawKey) >= 0
+	})
+	if i < len(t.vals) && t.vals[i].sameKey(e) {
+		if !overwrite {
+			return t, t.vals[i], true
+		}
+
+		y := t.copy()
+		y.vals = replaceEntry(t.vals, i, e)
+		return y, t.vals[i], true
+	}
+
+	if len(t.vals) < smallSize {
+		vals := make([]Entry, 0, len(t.vals)+1)
+		vals = append(vals, t.vals[:i]...)
+		vals = append(vals, e.resolve())
+
+		y := t.copy()
+		y.vals = append(vals, t.vals[i:]...)
+		y.size++
+		return y, Entry{}, false
+	}
+
+	// out of room, move every entry down into children
+	y := NewTNode(nil, nil)
+	for _, v := range t.vals {
+		y, _, _ = y.insertChild(v, true)
+	}
+
+	return y.insertChild(e, overwrite)
+}
+
+// insertChild adds e below the node the way insert does, without keeping it inline
+func (t *TNode) insertChild(e Entry, overwrite bool) (*TNode, Entry, bool) {
+	if t.depth >= maxDepth {
+		return t.insertBucket(e, overwrite)
+	}
 
 	// the path we use to insert the key
 	// these nodes will have to be reallocated
-	z := *t
-	y := &z
 	index := e.indexAtDepth(t.depth)
+	x := t.children[index]
 
 	// if the slot is open at this level, insert the e
-	if y.children[index] == nil {
-		// log.Println("Inserting new TNode", t.depth)
-		y.children[index] = NewTNode(y, []Entry{e})
-		return y
+	if x == nil {
+		y := t.copy()
+		y.children[index] = NewTNode(y, []Entry{e.resolve()})
+		y.size++
+		return y, Entry{}, false
 	}
 
-	// if we are at the max depth, start appending
-	if y.depth >= maxDepth {
-		// log.Println("Appending at ", t.depth)
-		y.vals = append(y.vals, e)
-		return y
-	}
-
-	x := y.children[index]
-
 	// check for a hash collision or that the key already exists
 	for i := 0; i < len(x.vals); i++ {
 		if x.vals[i].sameKey(e) {
-			y.children[index].vals[i] = e
-			return y
+			if !overwrite {
+				return t, x.vals[i], true
+			}
+
+			c := x.copy()
+			c.vals = replaceEntry(x.vals, i, e)
+			y := t.copy()
+			y.children[index] = c
+			return y, x.vals[i], true
 		}
 	}
 
-	y.children[index] = y.children[index].put(e)
-	return y
+	n, old, found := x.insert(e, overwrite)
+	if n == x {
+		return t, old, found
+	}
+
+	y := t.copy()
+	y.children[index] = n
+	y.size += n.size - x.size
+	return y, old, found
+}
+
+// insertBucket adds e to a node at maxDepth. Every key that gets this far has the same hash, so
+// the node keeps them in a linear bucket and never grows children.
+func (t *TNode) insertBucket(e Entry, overwrite bool) (*TNode, Entry, bool) {
+	for i := 0; i < len(t.vals); i++ {
+		if t.vals[i].sameKey(e) {
+			if !overwrite {
+				return t, t.vals[i], true
+			}
+
+			y := t.copy()
+			y.vals = replaceEntry(t.vals, i, e)
+			return y, t.vals[i], true
+		}
+	}
+
+	observe(EventCollision, 1)
+	y := t.copy()
+	vals := make([]Entry, len(t.vals), len(t.vals)+1)
+	copy(vals, t.vals)
+	y.vals = append(vals, e.resolve())
+	y.size++
+	return y, Entry{}, false
+}
+
+// leaf returns true if the node has no children
+func (t *TNode) leaf() bool {
+	for _, c := range t.children {
+		if c != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// copy returns a shallow copy of the node
+func (t *TNode) copy() *TNode {
+	observe(EventPathCopy, 1)
+	c := *t
+	return &c
+}
+
+// replaceEntry returns a copy of vals with the entry at i swapped for e
+func replaceEntry(vals []Entry, i int, e Entry) []Entry {
+	n := make([]Entry, len(vals))
+	copy(n, vals)
+	n[i] = e.resolve()
+	return n
 }
 
 // Get a value from the TNode if it exists and (nil, false) if it doesn't
 func (t *TNode) Get(key []byte) (interface{}, bool) {
+	return t.get(newEntry(key, nil))
+}
+
+func (t *TNode) get(e Entry) (interface{}, bool) {
 	y := t
-	e := newEntry(key, nil)
 
 	// if this part of the hash exists here, go deeper
-	y = y.children[e.indexAtDepth(y.depth)]
 	for y != nil {
 
 		// go through the list of elements to check to see if it is in here
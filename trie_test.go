@@ -1,6 +1,8 @@
 package immut
 
 import (
+	"encoding/binary"
+	"fmt"
 	"testing"
 
 	"github.com/eliothedeman/randutil"
@@ -53,6 +55,59 @@ func TestTrieDel(t *testing.T) {
 	})
 }
 
+func TestTrieOverwrite(t *testing.T) {
+	x := NewTrie()
+	keys := randBytes(1000)
+	for _, k := range keys {
+		x = x.Put(k, 1)
+	}
+
+	y := x
+	for _, k := range keys {
+		y = y.Put(k, 2)
+	}
+
+	if y.Size() != len(keys) {
+		t.Errorf("Expected %d got %d", len(keys), y.Size())
+	}
+
+	for _, k := range keys {
+		if v, _ := x.Get(k); v != 1 {
+			t.Fatalf("Persistance broken. Expected 1 got %v", v)
+		}
+
+		if v, _ := y.Get(k); v != 2 {
+			t.Fatalf("Expected 2 got %v", v)
+		}
+	}
+}
+
+func TestTrieDelAll(t *testing.T) {
+	x := NewTrie()
+	keys := randBytes(1000)
+	for _, k := range keys {
+		x = x.Put(k, 1)
+	}
+
+	y := x
+	for _, k := range keys {
+		y, _ = y.Del(k)
+		if _, found := y.Get(k); found {
+			t.Fatalf("%s should have been deleted", k)
+		}
+	}
+
+	if y.Size() != 0 {
+		t.Errorf("Expected 0 got %d", y.Size())
+	}
+
+	for _, k := range keys {
+		if _, found := x.Get(k); !found {
+			t.Fatalf("Persistance broken. %s should still be in the old trie", k)
+		}
+	}
+}
+
 func randStrs(count int) []string {
 	b := make([]string, count)
 	for i := 0; i < count; i++ {
@@ -101,3 +156,207 @@ func BenchmarkHashPut(b *testing.B) {
 		x[strs[i%len(strs)]] = randutil.Int()
 	}
 }
+
+func TestTriePutIfAbsent(t *testing.T) {
+	x := NewTrie()
+	keys := randBytes(1000)
+	for _, k := range keys {
+		x, _, _ = x.PutIfAbsent(k, 1)
+	}
+
+	for _, k := range keys {
+		y, v, inserted := x.PutIfAbsent(k, 2)
+		if inserted || v != 1 || y != x {
+			t.Fatalf("Expected %s to be left alone, got %v, %t", k, v, inserted)
+		}
+	}
+
+	if x.Size() != len(keys) {
+		t.Errorf("Expected %d got %d", len(keys), x.Size())
+	}
+}
+
+// checkCounts verifies every node's size matches the entries below it
+func checkCounts(t *testing.T, n *TNode) int {
+	t.Helper()
+	count := len(n.vals)
+	for _, c := range n.children {
+		if c != nil {
+			count += checkCounts(t, c)
+		}
+	}
+
+	if count != n.size {
+		t.Fatalf("Node at depth %d has size %d but holds %d entries", n.depth, n.size, count)
+	}
+
+	return count
+}
+
+func TestTrieSubtreeCounts(t *testing.T) {
+	x := NewTrie()
+	keys := randBytes(2000)
+	for _, k := range keys {
+		x = x.Put(k, 1)
+	}
+
+	// overwrites don't change the counts
+	for _, k := range keys[:500] {
+		x = x.Put(k, 2)
+	}
+	checkCounts(t, x.root)
+
+	for _, k := range keys[:1000] {
+		x, _ = x.Del(k)
+	}
+	checkCounts(t, x.root)
+
+	if x.Size() != 1000 {
+		t.Errorf("Expected 1000 got %d", x.Size())
+	}
+
+	for _, s := range x.Shard(3) {
+		checkCounts(t, s.root)
+	}
+}
+
+func TestTrieSmall(t *testing.T) {
+	x := NewTrie()
+	keys := randBytes(smallSize + 1)
+	for i, k := range keys {
+		x = x.Put(k, i)
+
+		if small := i < smallSize; x.root.leaf() != small {
+			t.Fatalf("Expected leaf root %t after %d puts", small, i+1)
+		}
+
+		checkCounts(t, x.root)
+		for j, k := range keys[:i+1] {
+			if v, found := x.Get(k); !found || v != j {
+				t.Fatalf("Expected %d got %v", j, v)
+			}
+		}
+	}
+
+	small := NewTrie().Put([]byte("b"), 1).Put([]byte("a"), 2).Put([]byte("b"), 3)
+	if small.Size() != 2 {
+		t.Errorf("Expected 2 got %d", small.Size())
+	}
+
+	if k := small.Keys(); string(k[0]) != "a" || string(k[1]) != "b" {
+		t.Errorf("Expected small tries to keep keys sorted, got %s", k)
+	}
+
+	n, _ := small.Del([]byte("a"))
+	if _, found := n.Get([]byte("a")); found || n.Size() != 1 {
+		t.Error("Expected a to be deleted")
+	}
+
+	if _, found := small.Get([]byte("c")); found {
+		t.Error("Expected c to be missing")
+	}
+
+	var after []string
+	small.RangeFrom([]byte("aa"), func(k []byte, _ interface{}) bool {
+		after = append(after, string(k))
+		return true
+	})
+
+	if len(after) != 1 || after[0] != "b" {
+		t.Errorf("Expected a missing key to resume at its place in a small trie, got %v", after)
+	}
+
+	total := 0
+	for _, s := range small.Shard(2) {
+		total += s.Size()
+	}
+
+	if total != 2 {
+		t.Errorf("Expected shards of a small trie to hold 2 keys, got %d", total)
+	}
+}
+
+func BenchmarkHashMapSmallPutGet(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h := NewHashMap().Put("host", "a").Put("region", "b").Put("service", "c")
+		h.Get("region")
+	}
+}
+
+// prefixHasher uses the first 4 bytes of a key as its hash, so tests can pick exactly which
+// keys collide and how deep they go
+type prefixHasher struct{}
+
+func (prefixHasher) Hash(key []byte) uint32 {
+	var b [4]byte
+	copy(b[:], key)
+	return binary.LittleEndian.Uint32(b[:])
+}
+
+func TestTrieMaxDepth(t *testing.T) {
+	x := NewTrieWithHasher(constHasher{})
+	for i := 0; i < 100; i++ {
+		x = x.Put([]byte(fmt.Sprint(i)), i)
+	}
+
+	if levels := len(x.HashStats().Levels); levels != TrieMaxDepth+1 {
+		t.Errorf("Expected keys with the same hash to stop at depth %d, got %d levels", TrieMaxDepth, levels)
+	}
+
+	for i := 0; i < 100; i++ {
+		if v, found := x.Get([]byte(fmt.Sprint(i))); !found || v != i {
+			t.Fatalf("Expected %d got %v", i, v)
+		}
+	}
+
+	for i := 0; i < 100; i += 2 {
+		x, _ = x.Del([]byte(fmt.Sprint(i)))
+	}
+
+	checkCounts(t, x.root)
+	if x.Size() != 50 {
+		t.Errorf("Expected 50 got %d", x.Size())
+	}
+}
+
+func FuzzTrieMaxDepth(f *testing.F) {
+	f.Add([]byte{1, 2, 3, 4, 0, 1, 2, 3, 4, 1, 1, 2, 3, 5, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+
+		// every 5 bytes are a key, the first 4 of which are its hash
+		x := NewTrieWithHasher(prefixHasher{})
+		model := map[string]int{}
+		for i := 0; i+5 <= len(data); i += 5 {
+			k := data[i : i+5]
+			x = x.Put(k, i)
+			model[string(k)] = i
+		}
+
+		if levels := len(x.HashStats().Levels); levels > TrieMaxDepth+1 {
+			t.Fatalf("Expected at most %d levels got %d", TrieMaxDepth+1, levels)
+		}
+
+		checkCounts(t, x.root)
+		if x.Size() != len(model) {
+			t.Fatalf("Expected %d got %d", len(model), x.Size())
+		}
+
+		deleted := 0
+		for k, want := range model {
+			if v, found := x.Get([]byte(k)); !found || v != want {
+				t.Fatalf("Expected %d at %x got %v", want, k, v)
+			}
+
+			if deleted%2 == 0 {
+				x, _ = x.Del([]byte(k))
+				if _, found := x.Get([]byte(k)); found {
+					t.Fatalf("Expected %x to be deleted", k)
+				}
+			}
+			deleted++
+		}
+
+		checkCounts(t, x.root)
+	})
+}
@@ -0,0 +1,23 @@
+package immut
+
+// As returns the value stored at k asserted to T. It returns false if k is missing or its value
+// is not a T.
+func As[T any](h *HashMap, k interface{}) (T, bool) {
+	v, found := h.Get(k)
+	if !found {
+		var zero T
+		return zero, false
+	}
+
+	t, ok := v.(T)
+	return t, ok
+}
+
+// FilterType returns a map of the k,v pairs of h whose value is a T. Every value in the result
+// can be asserted to T, which helps when moving interface{} heavy code over to typed values.
+func FilterType[T any](h *HashMap) *HashMap {
+	return h.RetainWhere(func(_, v interface{}) bool {
+		_, ok := v.(T)
+		return ok
+	})
+}
@@ -0,0 +1,39 @@
+package immut
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAs(t *testing.T) {
+	h := NewHashMap().Put("name", "svc").Put("port", 80)
+
+	if s, ok := As[string](h, "name"); !ok || s != "svc" {
+		t.Errorf("Expected svc got %q", s)
+	}
+
+	if _, ok := As[string](h, "port"); ok {
+		t.Error("Expected the assertion to fail for an int")
+	}
+
+	if _, ok := As[int](h, "missing"); ok {
+		t.Error("Expected a missing key to fail")
+	}
+}
+
+func TestFilterType(t *testing.T) {
+	h := NewHashMap().Put("a", 1).Put("b", "two").Put("c", 3).Put("d", fmt.Errorf("x"))
+
+	ints := FilterType[int](h)
+	if ints.Len() != 2 {
+		t.Errorf("Expected 2 ints got %v", ints.Entries())
+	}
+
+	if errs := FilterType[error](h); errs.Len() != 1 {
+		t.Errorf("Expected interface types to work, got %v", errs.Entries())
+	}
+
+	if FilterType[interface{}](h) != h {
+		t.Error("Expected the map itself back when every value matches")
+	}
+}
@@ -1,10 +1,19 @@
 package immut
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+	"fmt"
+)
 
 // Vector is a constant time lookup with constand time appending and linier prepending
 type Vector struct {
 	root *TNode
+
+	// offset is where index 0 of the vector is stored in root. Slice moves it forward instead
+	// of reinserting every element at a new index.
+	offset int
+
+	// size is one past the highest index holding an element
 	size int
 }
 
@@ -25,41 +34,101 @@ func newVectorEntry(index int, val interface{}) Entry {
 	return e
 }
 
-// Size returns the number of elements in the vector
+// Size returns one past the highest index holding an element, which is the number of elements
+// unless the vector has holes
 func (v *Vector) Size() int {
 	return v.size
 }
 
-// Put the given value at the given index
+// Put the given value at the given index. Putting at an index below Size replaces the value
+// without changing the size, putting past it grows the size to index+1 and leaves any indexes
+// skipped over empty. Like indexing a slice, a negative index panics.
 func (v *Vector) Put(index int, val interface{}) *Vector {
-	r := v.root.put(newVectorEntry(index, val))
+	if index < 0 {
+		panic(fmt.Sprintf("immut: vector index %d is negative", index))
+	}
+
+	size := v.size
+	if index >= size {
+		size = index + 1
+	}
+
+	r, _ := v.root.put(newVectorEntry(v.offset+index, val))
 	return &Vector{
-		root: r,
-		size: v.size + 1,
+		root:   r,
+		offset: v.offset,
+		size:   size,
 	}
 }
 
 // Get the value at the givne index
 func (v *Vector) Get(index int) (interface{}, bool) {
-	b := make([]byte, 4)
-	binary.LittleEndian.PutUint32(b, uint32(index))
-	return v.root.Get(b)
+	if index < 0 {
+		return nil, false
+	}
+
+	return v.root.get(newVectorEntry(v.offset+index, nil))
 }
 
-// Slice returns a subslice of the vector.
-// same as the built in slice operations mySlice[1:10]
+// Slice returns the elements from start through end, both included, as a new vector indexed
+// from 0. Indexes outside the vector are left empty, so the element at start always ends up at
+// index 0. SliceRange is the bounds checked version with the built in mySlice[start:end]
+// semantics.
 func (v *Vector) Slice(start, end int) *Vector {
+	return v.slice(start, end+1)
+}
 
-	n := NewVector()
+// SliceRange returns the elements in [start, end) as a new vector indexed from 0, the same as
+// the built in mySlice[start:end]. It returns IndexOutOfRange unless 0 <= start <= end <= Size.
+func (v *Vector) SliceRange(start, end int) (*Vector, error) {
+	if start < 0 || end > v.Size() || start > end {
+		return nil, IndexOutOfRange
+	}
 
-	// TODO Don't do all of these allocations
-	count := 0
-	for i := start; i <= end; i++ {
-		x, found := v.Get(i)
-		if found {
-			n = n.Put(count, x)
-		}
-		count++
+	return v.slice(start, end), nil
+}
+
+// slice returns the elements in [start, end) indexed from start. The new vector shares every
+// node that doesn't hold an element outside the range, and no element is reinserted.
+func (v *Vector) slice(start, end int) *Vector {
+	offset := v.offset + start
+	if start < 0 {
+		start = 0
 	}
+	if end > v.Size() {
+		end = v.Size()
+	}
+
+	n := &Vector{
+		offset: offset,
+	}
+
+	lo, hi := uint32(v.offset+start), uint32(v.offset+end)
+	n.root = v.root.deleteWhere(func(e Entry) bool {
+		if e.hashedKey < lo || e.hashedKey >= hi {
+			return true
+		}
+
+		if i := int(e.hashedKey-uint32(offset)) + 1; i > n.size {
+			n.size = i
+		}
+		return false
+	}, nil)
+
 	return n
 }
+
+// dropFirst returns the vector without its first element. Every other element moves down an
+// index by moving the offset, nothing is reinserted.
+func (v *Vector) dropFirst() *Vector {
+	r, _, found := v.root.del(newVectorEntry(v.offset, nil))
+	if !found {
+		return v
+	}
+
+	return &Vector{
+		root:   r,
+		offset: v.offset + 1,
+		size:   v.size - 1,
+	}
+}
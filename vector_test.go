@@ -0,0 +1,139 @@
+package immut
+
+import "testing"
+
+func TestVectorPutOverwrite(t *testing.T) {
+	v := NewVector().Put(0, "a").Put(1, "b")
+	n := v.Put(0, "c")
+
+	if n.Size() != 2 {
+		t.Errorf("Expected overwriting to keep the size at 2, got %d", n.Size())
+	}
+
+	if x, _ := n.Get(0); x != "c" {
+		t.Errorf("Expected c got %v", x)
+	}
+
+	if x, _ := v.Get(0); x != "a" {
+		t.Errorf("Expected the old version to keep a, got %v", x)
+	}
+
+	if _, found := v.Get(-1); found {
+		t.Error("Expected negative indexes to be missing")
+	}
+}
+
+func TestVectorSlice(t *testing.T) {
+	v := NewVector()
+	for i := 0; i < 1000; i++ {
+		v = v.Put(i, i)
+	}
+
+	s, err := v.SliceRange(100, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Size() != 100 {
+		t.Fatalf("Expected 100 got %d", s.Size())
+	}
+
+	for i := 0; i < s.Size(); i++ {
+		if x, _ := s.Get(i); x != i+100 {
+			t.Fatalf("Expected %d at %d got %v", i+100, i, x)
+		}
+	}
+
+	if _, found := s.Get(100); found {
+		t.Error("Expected the end of the slice to be excluded")
+	}
+
+	// slices of slices and appends keep counting from the new start
+	s, _ = s.SliceRange(10, 20)
+	s = s.Put(s.Size(), "end")
+	if x, _ := s.Get(0); x != 110 || s.Size() != 11 {
+		t.Errorf("Expected 110 and 11 got %v and %d", x, s.Size())
+	}
+
+	if x, _ := s.Get(10); x != "end" {
+		t.Errorf("Expected end got %v", x)
+	}
+
+	if all, _ := v.SliceRange(0, v.Size()); all.root != v.root {
+		t.Error("Expected slicing the whole vector to share the root")
+	}
+
+	for _, r := range [][2]int{{-1, 5}, {5, 1001}, {6, 5}} {
+		if _, err := v.SliceRange(r[0], r[1]); err != IndexOutOfRange {
+			t.Errorf("Expected IndexOutOfRange for %v got %v", r, err)
+		}
+	}
+}
+
+func TestVectorSliceGob(t *testing.T) {
+	v := NewVector().Put(0, "a").Put(1, "b").Put(2, "c")
+	s, _ := v.SliceRange(1, 3)
+
+	b, err := s.GobEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := NewVector()
+	if err := out.GobDecode(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if x, _ := out.Get(0); x != "b" || out.Size() != 2 {
+		t.Errorf("Expected b and 2 got %v and %d", x, out.Size())
+	}
+}
+
+func TestVectorSparse(t *testing.T) {
+	v := NewVector().Put(0, "a").Put(5, "f")
+	if v.Size() != 6 {
+		t.Errorf("Expected 6 got %d", v.Size())
+	}
+
+	if _, found := v.Get(3); found {
+		t.Error("Expected the skipped indexes to be empty")
+	}
+
+	if n := v.Put(2, "c"); n.Size() != 6 {
+		t.Errorf("Expected filling a hole to keep the size at 6, got %d", n.Size())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Put at a negative index to panic")
+		}
+	}()
+	v.Put(-1, "x")
+}
+
+func TestVectorSliceInclusive(t *testing.T) {
+	v := NewVector().Put(0, "a").Put(1, "b").Put(2, "c").Put(4, "e")
+
+	s := v.Slice(1, 2)
+	if x, _ := s.Get(1); x != "c" || s.Size() != 2 {
+		t.Errorf("Expected c and 2 got %v and %d", x, s.Size())
+	}
+
+	// indexes outside the vector stay empty and keep the rest in place
+	s = v.Slice(-1, 10)
+	if x, _ := s.Get(1); x != "a" || s.Size() != 6 {
+		t.Errorf("Expected a and 6 got %v and %d", x, s.Size())
+	}
+
+	if _, found := s.Get(4); found {
+		t.Error("Expected the hole to stay empty")
+	}
+
+	if x, _ := s.Get(5); x != "e" {
+		t.Errorf("Expected e got %v", x)
+	}
+
+	if s := v.Slice(3, 1); s.Size() != 0 {
+		t.Errorf("Expected an empty vector got %d", s.Size())
+	}
+}
@@ -0,0 +1,89 @@
+package immut
+
+// A VectorView is a read only window onto the range [start, end) of a Vector. It shares the
+// vector's nodes, so taking a view never copies any elements.
+type VectorView struct {
+	v     *Vector
+	start int
+	end   int
+}
+
+// View returns a view of the vector's [start, end) range, clamped to [0, Size)
+func (v *Vector) View(start, end int) VectorView {
+	if start < 0 {
+		start = 0
+	}
+
+	if end > v.Size() {
+		end = v.Size()
+	}
+
+	if end < start {
+		end = start
+	}
+
+	return VectorView{
+		v:     v,
+		start: start,
+		end:   end,
+	}
+}
+
+// Chunks splits the vector into consecutive views of n elements. The last view holds whatever
+// is left over and may be shorter.
+func (v *Vector) Chunks(n int) []VectorView {
+	if n <= 0 {
+		return nil
+	}
+
+	chunks := make([]VectorView, 0, (v.Size()+n-1)/n)
+	for i := 0; i < v.Size(); i += n {
+		chunks = append(chunks, v.View(i, i+n))
+	}
+
+	return chunks
+}
+
+// Windows returns every view of n consecutive elements, each one starting an element after the
+// last. A vector shorter than n has no windows.
+func (v *Vector) Windows(n int) []VectorView {
+	if n <= 0 || n > v.Size() {
+		return nil
+	}
+
+	windows := make([]VectorView, 0, v.Size()-n+1)
+	for i := 0; i+n <= v.Size(); i++ {
+		windows = append(windows, v.View(i, i+n))
+	}
+
+	return windows
+}
+
+// Len returns the number of elements in the view
+func (w VectorView) Len() int {
+	return w.end - w.start
+}
+
+// Get returns the value at the given index of the view
+func (w VectorView) Get(index int) (interface{}, bool) {
+	if index < 0 || index >= w.Len() {
+		return nil, false
+	}
+
+	return w.v.Get(w.start + index)
+}
+
+// Each runs f on every index, value pair in the view in order
+func (w VectorView) Each(f func(index int, val interface{})) {
+	for i := 0; i < w.Len(); i++ {
+		if x, found := w.Get(i); found {
+			f(i, x)
+		}
+	}
+}
+
+// Vector returns the view as a new Vector indexed from 0, sharing nodes with the vector it views
+// like Vector.SliceRange
+func (w VectorView) Vector() *Vector {
+	return w.v.slice(w.start, w.end)
+}
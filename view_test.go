@@ -0,0 +1,76 @@
+package immut
+
+import "testing"
+
+func vectorOf(n int) *Vector {
+	v := NewVector()
+	for i := 0; i < n; i++ {
+		v = v.Put(i, i)
+	}
+
+	return v
+}
+
+func TestVectorChunks(t *testing.T) {
+	v := vectorOf(10)
+	chunks := v.Chunks(4)
+
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 got %d", len(chunks))
+	}
+
+	want := []int{4, 4, 2}
+	next := 0
+	for i, c := range chunks {
+		if c.Len() != want[i] {
+			t.Errorf("Expected chunk %d to have %d got %d", i, want[i], c.Len())
+		}
+
+		c.Each(func(_ int, val interface{}) {
+			if val != next {
+				t.Errorf("Expected %d got %v", next, val)
+			}
+			next++
+		})
+	}
+
+	if v.Chunks(0) != nil {
+		t.Error("Expected no chunks of size 0")
+	}
+}
+
+func TestVectorWindows(t *testing.T) {
+	v := vectorOf(5)
+	windows := v.Windows(3)
+
+	if len(windows) != 3 {
+		t.Fatalf("Expected 3 got %d", len(windows))
+	}
+
+	for i, w := range windows {
+		for j := 0; j < 3; j++ {
+			if x, _ := w.Get(j); x != i+j {
+				t.Errorf("Expected %d got %v", i+j, x)
+			}
+		}
+
+		if _, found := w.Get(3); found {
+			t.Error("Get past the end of a view should fail")
+		}
+	}
+
+	if v.Windows(6) != nil {
+		t.Error("Expected no windows larger than the vector")
+	}
+}
+
+func TestVectorViewVector(t *testing.T) {
+	v := vectorOf(10).View(3, 7).Vector()
+	if v.Size() != 4 {
+		t.Errorf("Expected 4 got %d", v.Size())
+	}
+
+	if x, _ := v.Get(0); x != 3 {
+		t.Errorf("Expected 3 got %v", x)
+	}
+}
@@ -0,0 +1,72 @@
+package immut
+
+import "iter"
+
+// A KeysView is a read only view of the keys in a HashMap. It doesn't copy anything out of the
+// map, so checking membership or streaming over the keys costs no allocations up front.
+type KeysView struct {
+	h *HashMap
+}
+
+// KeysView returns a view of the keys in the map
+func (h *HashMap) KeysView() KeysView {
+	return KeysView{
+		h: h,
+	}
+}
+
+// Len returns the number of keys
+func (k KeysView) Len() int {
+	return k.h.Len()
+}
+
+// Contains returns true if the key is in the map
+func (k KeysView) Contains(key interface{}) bool {
+	_, found := k.h.Get(key)
+	return found
+}
+
+// All returns an iterator over the keys
+func (k KeysView) All() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		k.h.t.root.eachUntil(func(e Entry) bool {
+			return yield(e.value.(Pair).Key)
+		})
+	}
+}
+
+// A ValuesView is a read only view of the values in a HashMap
+type ValuesView struct {
+	h *HashMap
+}
+
+// ValuesView returns a view of the values in the map
+func (h *HashMap) ValuesView() ValuesView {
+	return ValuesView{
+		h: h,
+	}
+}
+
+// Len returns the number of values
+func (v ValuesView) Len() int {
+	return v.h.Len()
+}
+
+// All returns an iterator over the values
+func (v ValuesView) All() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		v.h.t.root.eachUntil(func(e Entry) bool {
+			return yield(e.value.(Pair).Val)
+		})
+	}
+}
+
+// All returns an iterator over the k,v pairs in the map
+func (h *HashMap) All() iter.Seq2[interface{}, interface{}] {
+	return func(yield func(interface{}, interface{}) bool) {
+		h.t.root.eachUntil(func(e Entry) bool {
+			p := e.value.(Pair)
+			return yield(p.Key, p.Val)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package immut
+
+import "testing"
+
+func TestKeysView(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 50; i++ {
+		h = h.Put(i, i*2)
+	}
+
+	k := h.KeysView()
+	if k.Len() != 50 {
+		t.Errorf("Expected 50 got %d", k.Len())
+	}
+
+	if !k.Contains(10) || k.Contains(50) {
+		t.Error("Contains should only report keys in the map")
+	}
+
+	seen := map[interface{}]bool{}
+	for key := range k.All() {
+		seen[key] = true
+	}
+
+	if len(seen) != 50 {
+		t.Errorf("Expected 50 keys got %d", len(seen))
+	}
+
+	// stop early
+	count := 0
+	for range k.All() {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+
+	if count != 3 {
+		t.Errorf("Expected 3 got %d", count)
+	}
+}
+
+func TestValuesView(t *testing.T) {
+	h := NewHashMap().Put("a", 1).Put("b", 2)
+
+	sum := 0
+	for v := range h.ValuesView().All() {
+		sum += v.(int)
+	}
+
+	if sum != 3 || h.ValuesView().Len() != 2 {
+		t.Errorf("Expected 3 got %d", sum)
+	}
+}
+
+func TestHashMapAll(t *testing.T) {
+	h := NewHashMap().Put("a", 1).Put("b", 2)
+
+	for k, v := range h.All() {
+		if want, _ := h.Get(k); want != v {
+			t.Errorf("Expected %v got %v", want, v)
+		}
+	}
+}
+
+func BenchmarkKeysViewContains(b *testing.B) {
+	h := NewHashMap()
+	for i := 0; i < 10000; i++ {
+		h = h.Put(i, i)
+	}
+
+	k := h.KeysView()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		k.Contains(i % 10000)
+	}
+}
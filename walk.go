@@ -0,0 +1,39 @@
+package immut
+
+// Walk visits every node of the trie depth first, starting at the root. f is passed the depth of
+// the node, the child index taken at every level on the way down to it, and the k,v pairs stored
+// in the node with their raw []byte keys. Returning false skips the node's children.
+//
+// The path slice is reused between calls, copy it if it has to outlive the call.
+func (t *Trie) Walk(f func(depth int, path []int, pairs []Pair) bool) {
+	t.root.walk(make([]int, 0, maxDepth+1), f)
+}
+
+func (t *TNode) walk(path []int, f func(depth int, path []int, pairs []Pair) bool) {
+	pairs := make([]Pair, len(t.vals))
+	for i, e := range t.vals {
+		pairs[i] = Pair{Key: e.rawKey, Val: e.value}
+	}
+
+	if !f(len(path), path, pairs) {
+		return
+	}
+
+	for i := 0; i < len(t.children); i++ {
+		if t.children[i] != nil {
+			t.children[i].walk(append(path, i), f)
+		}
+	}
+}
+
+// Walk visits every node of the map's trie like Trie.Walk, but passes the map's own keys and
+// values instead of their byte representation
+func (h *HashMap) Walk(f func(depth int, path []int, pairs []Pair) bool) {
+	h.t.Walk(func(depth int, path []int, pairs []Pair) bool {
+		for i := range pairs {
+			pairs[i] = pairs[i].Val.(Pair)
+		}
+
+		return f(depth, path, pairs)
+	})
+}
@@ -0,0 +1,68 @@
+package immut
+
+import "testing"
+
+func TestTrieWalk(t *testing.T) {
+	x := NewTrie()
+	for _, k := range randBytes(500) {
+		x = x.Put(k, true)
+	}
+
+	count := 0
+	maxSeen := 0
+	x.Walk(func(depth int, path []int, pairs []Pair) bool {
+		if len(path) != depth {
+			t.Fatalf("Expected a path of length %d got %v", depth, path)
+		}
+
+		if depth > maxSeen {
+			maxSeen = depth
+		}
+		count += len(pairs)
+		return true
+	})
+
+	if count != x.Size() {
+		t.Errorf("Expected %d pairs got %d", x.Size(), count)
+	}
+
+	if maxSeen == 0 {
+		t.Error("Expected to walk below the root")
+	}
+}
+
+func TestTrieWalkSkip(t *testing.T) {
+	x := NewTrie()
+	for _, k := range randBytes(500) {
+		x = x.Put(k, true)
+	}
+
+	x.Walk(func(depth int, path []int, pairs []Pair) bool {
+		if depth > 1 {
+			t.Fatalf("Children of depth 1 nodes should have been skipped")
+		}
+		return depth < 1
+	})
+}
+
+func TestHashMapWalk(t *testing.T) {
+	h := NewHashMap()
+	for i := 0; i < 100; i++ {
+		h = h.Put(i, i*2)
+	}
+
+	seen := map[interface{}]bool{}
+	h.Walk(func(depth int, path []int, pairs []Pair) bool {
+		for _, p := range pairs {
+			if p.Val != p.Key.(int)*2 {
+				t.Errorf("Expected %d got %v", p.Key.(int)*2, p.Val)
+			}
+			seen[p.Key] = true
+		}
+		return true
+	})
+
+	if len(seen) != 100 {
+		t.Errorf("Expected 100 keys got %d", len(seen))
+	}
+}
@@ -0,0 +1,57 @@
+package immut
+
+import "errors"
+
+var (
+	LengthMismatch = errors.New("keys and values have different lengths")
+)
+
+// Zip builds a HashMap mapping keys[i] to vals[i]
+func Zip(keys, vals []interface{}) (*HashMap, error) {
+	if len(keys) != len(vals) {
+		return nil, LengthMismatch
+	}
+
+	h := NewHashMap()
+	for i := range keys {
+		h = h.Put(keys[i], vals[i])
+	}
+
+	return h, nil
+}
+
+// Unzip returns the keys and values of the map such that vals[i] is stored at keys[i]
+func Unzip(h *HashMap) ([]interface{}, []interface{}) {
+	keys := make([]interface{}, 0, h.Len())
+	vals := make([]interface{}, 0, h.Len())
+	h.Each(func(k, v interface{}) {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	})
+
+	return keys, vals
+}
+
+// Invert returns a HashMap mapping every value to its key. If several keys share a value, it is
+// undefined which of them ends up in the result.
+func Invert(h *HashMap) *HashMap {
+	n := NewHashMap()
+	h.Each(func(k, v interface{}) {
+		n = n.Put(v, k)
+	})
+
+	return n
+}
+
+// InvertMulti returns a HashMap mapping every value to a []interface{} of all the keys that
+// store it
+func InvertMulti(h *HashMap) *HashMap {
+	n := NewHashMap()
+	h.Each(func(k, v interface{}) {
+		keys, _ := n.Get(v)
+		ks, _ := keys.([]interface{})
+		n = n.Put(v, append(ks, k))
+	})
+
+	return n
+}
@@ -0,0 +1,69 @@
+package immut
+
+import "testing"
+
+func TestZipUnzip(t *testing.T) {
+	keys := []interface{}{"a", "b", "c", 1, 2.5}
+	vals := []interface{}{1, 2, 3, "one", "two and a half"}
+
+	h, err := Zip(keys, vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h.Len() != len(keys) {
+		t.Errorf("Expected %d got %d", len(keys), h.Len())
+	}
+
+	k, v := Unzip(h)
+	if len(k) != len(keys) || len(v) != len(vals) {
+		t.Fatalf("Expected %d pairs got %d, %d", len(keys), len(k), len(v))
+	}
+
+	for i := range k {
+		if want, _ := h.Get(k[i]); want != v[i] {
+			t.Errorf("Expected %v at %v got %v", want, k[i], v[i])
+		}
+	}
+
+	if _, err := Zip(keys, vals[1:]); err != LengthMismatch {
+		t.Errorf("Expected %v got %v", LengthMismatch, err)
+	}
+}
+
+func TestInvert(t *testing.T) {
+	h := NewHashMap().Put("a", 1).Put("b", 2)
+	i := Invert(h)
+
+	if v, _ := i.Get(1); v != "a" {
+		t.Errorf("Expected a got %v", v)
+	}
+
+	if v, _ := i.Get(2); v != "b" {
+		t.Errorf("Expected b got %v", v)
+	}
+}
+
+func TestInvertMulti(t *testing.T) {
+	h := NewHashMap().Put("a", 1).Put("b", 2).Put("c", 1)
+	i := InvertMulti(h)
+
+	if i.Len() != 2 {
+		t.Errorf("Expected 2 got %d", i.Len())
+	}
+
+	ones, _ := i.Get(1)
+	seen := map[interface{}]bool{}
+	for _, k := range ones.([]interface{}) {
+		seen[k] = true
+	}
+
+	if len(seen) != 2 || !seen["a"] || !seen["c"] {
+		t.Errorf("Expected [a c] got %v", ones)
+	}
+
+	twos, _ := i.Get(2)
+	if len(twos.([]interface{})) != 1 {
+		t.Errorf("Expected [b] got %v", twos)
+	}
+}